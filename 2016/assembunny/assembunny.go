@@ -0,0 +1,174 @@
+// Copyright 2016 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package assembunny implements the 2016 Advent of Code assembunny language,
+// which is used by days 12, 23 and 25.
+package assembunny
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// An Op is an assembunny operation.
+type Op uint8
+
+// The assembunny operations.  In the descriptions, X and Y are the operands.
+const (
+	Cpy Op = iota // Y = X
+	Inc           // X++
+	Dec           // X--
+	Jnz           // jumps by Y if X != 0
+	Tgl           // toggles the instruction X away
+	Out           // transmits X
+)
+
+var opNames = [...]string{
+	Cpy: "cpy",
+	Inc: "inc",
+	Dec: "dec",
+	Jnz: "jnz",
+	Tgl: "tgl",
+	Out: "out",
+}
+
+// String returns the name of the operation, like "cpy".
+func (op Op) String() string {
+	if int(op) >= len(opNames) {
+		return fmt.Sprintf("Op(%d)", uint8(op))
+	}
+	return opNames[op]
+}
+
+// Unary returns whether the operation takes a single operand.
+func (op Op) Unary() bool {
+	return op == Inc || op == Dec || op == Tgl || op == Out
+}
+
+// Toggle returns the operation an instruction becomes when it is toggled by
+// tgl: inc becomes dec and every other unary operation becomes inc, while jnz
+// becomes cpy and every other binary operation becomes jnz.
+func (op Op) Toggle() Op {
+	switch {
+	case op == Inc:
+		return Dec
+	case op.Unary():
+		return Inc
+	case op == Jnz:
+		return Cpy
+	}
+	return Jnz
+}
+
+// NumRegisters is the number of registers, which are named a through d.
+const NumRegisters = 4
+
+// An Operand is either a register (named by a letter from a to d) or an
+// immediate value.
+type Operand struct {
+	Reg   byte // register name, or 0 for an immediate
+	Value int  // immediate value
+}
+
+// String returns the operand in its source form.
+func (o Operand) String() string {
+	if o.Reg != 0 {
+		return string(o.Reg)
+	}
+	return strconv.Itoa(o.Value)
+}
+
+// An Instruction is an operation along with its operands.  Y is unused for
+// unary operations.
+type Instruction struct {
+	Op   Op
+	X, Y Operand
+}
+
+// Valid returns whether the instruction can be executed.  Toggling can
+// produce instructions which write to an immediate, like "cpy 1 2", which are
+// skipped.
+func (i Instruction) Valid() bool {
+	switch i.Op {
+	case Cpy:
+		return i.Y.Reg != 0
+	case Inc, Dec:
+		return i.X.Reg != 0
+	}
+	return true
+}
+
+// String returns the instruction in its source form, like "cpy a b".
+func (i Instruction) String() string {
+	if i.Op.Unary() {
+		return fmt.Sprintf("%v %v", i.Op, i.X)
+	}
+	return fmt.Sprintf("%v %v %v", i.Op, i.X, i.Y)
+}
+
+// A Program is a list of instructions.
+type Program []Instruction
+
+// Parse parses a program in its source form, one instruction per line.
+// Leading and trailing whitespace on each line is ignored.
+//
+// Instructions which are only valid as the result of a toggle, like
+// "cpy 1 2", are accepted so that any program state can be round-tripped.
+func Parse(t advent.OptionalT, source string) Program {
+	var prog Program
+	add := func(op Op, x, y string) {
+		instr := Instruction{Op: op, X: parseOperand(t, x)}
+		if y != "" {
+			instr.Y = parseOperand(t, y)
+		}
+		prog = append(prog, instr)
+	}
+	unary := func(op Op) func(x string) {
+		return func(x string) { add(op, x, "") }
+	}
+	binary := func(op Op) func(x, y string) {
+		return func(x, y string) { add(op, x, y) }
+	}
+	advent.Lines(source).Dispatch(t,
+		advent.On(`^\s*cpy (\S+) (\S+)\s*$`, binary(Cpy)),
+		advent.On(`^\s*inc (\S+)\s*$`, unary(Inc)),
+		advent.On(`^\s*dec (\S+)\s*$`, unary(Dec)),
+		advent.On(`^\s*jnz (\S+) (\S+)\s*$`, binary(Jnz)),
+		advent.On(`^\s*tgl (\S+)\s*$`, unary(Tgl)),
+		advent.On(`^\s*out (\S+)\s*$`, unary(Out)),
+		advent.On(`^\s*$`, func() {}),
+	)
+	return prog
+}
+
+func parseOperand(t advent.OptionalT, s string) Operand {
+	if len(s) == 1 && s[0] >= 'a' && s[0] < 'a'+NumRegisters {
+		return Operand{Reg: s[0]}
+	}
+	var o Operand
+	advent.Scanner(s).Extract(t, `^(-?\d+)$`, &o.Value)
+	return o
+}
+
+// String returns the program in its source form.
+func (p Program) String() string {
+	var sb strings.Builder
+	for _, instr := range p {
+		fmt.Fprintln(&sb, instr)
+	}
+	return sb.String()
+}
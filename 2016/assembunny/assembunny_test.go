@@ -0,0 +1,129 @@
+// Copyright 2016 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assembunny
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// multiply computes a = b * c the slow way, using a multiplication loop.
+const multiply = `cpy 0 a
+cpy b c
+inc a
+dec c
+jnz c -2
+dec d
+jnz d -5`
+
+const toggle = `cpy 2 a
+tgl a
+tgl a
+tgl a
+cpy 1 a
+dec a
+dec a`
+
+func TestParse(t *testing.T) {
+	prog := Parse(t, "\n\t"+toggle+"\n\tout a\n\tjnz 1 c\n")
+	want := "cpy 2 a\ntgl a\ntgl a\ntgl a\ncpy 1 a\ndec a\ndec a\nout a\njnz 1 c\n"
+	if diff := cmp.Diff(prog.String(), want); diff != "" {
+		t.Errorf("String() differs: (-got +want)\n%s", diff)
+	}
+	if got, want := prog[8], (Instruction{Jnz, Operand{Value: 1}, Operand{Reg: 'c'}}); got != want {
+		t.Errorf("prog[8] = %v, want %v", got, want)
+	}
+}
+
+func TestToggle(t *testing.T) {
+	m := NewMachine(Parse(t, toggle))
+	if !m.Run() {
+		t.Fatalf("Run() stopped without terminating")
+	}
+	if got, want := m.Registers, [NumRegisters]int{3, 0, 0, 0}; got != want {
+		t.Errorf("Registers = %v, want %v", got, want)
+	}
+
+	want := "cpy 2 a\ntgl a\ntgl a\ninc a\njnz 1 a\ndec a\ndec a\n"
+	if diff := cmp.Diff(m.Program.String(), want); diff != "" {
+		t.Errorf("Program differs: (-got +want)\n%s", diff)
+	}
+	if got, want := Parse(t, toggle).String(), toggle+"\n"; got != want {
+		t.Errorf("NewMachine modified its input: %q, want %q", got, want)
+	}
+
+	// Toggling jnz can produce an invalid cpy, which is skipped.
+	m = NewMachine(Parse(t, "tgl 1\njnz 1 2\ninc a"))
+	m.Run()
+	if got, want := m.Program[1].String(), "cpy 1 2"; got != want {
+		t.Errorf("toggled jnz = %q, want %q", got, want)
+	}
+	if got, want := m.Registers[0], 1; got != want {
+		t.Errorf("a = %v, want %v", got, want)
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	tests := []struct {
+		name string
+		prog string
+		regs [NumRegisters]int
+	}{
+		{"multiply", multiply, [NumRegisters]int{0, 6, 0, 7}},
+		{"multiply by immediate", "cpy 5 c\ninc a\ndec c\njnz c -2\ndec d\njnz d -5", [NumRegisters]int{1, 0, 0, 3}},
+		{"dec before inc", "dec c\ninc a\njnz c -2", [NumRegisters]int{1, 0, 9, 0}},
+		{"add", "inc a\ndec b\njnz b -2\ninc d", [NumRegisters]int{1, 4, 0, 0}},
+		{"toggled", "tgl 3\n" + multiply, [NumRegisters]int{0, 6, 0, 7}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prog := Parse(t, test.prog)
+			slow, fast := NewMachine(prog), NewMachine(prog)
+			slow.Registers, fast.Registers = test.regs, test.regs
+			fast.Optimize = true
+			slow.Run()
+			fast.Run()
+
+			if got, want := fast.Registers, slow.Registers; got != want {
+				t.Errorf("optimized Registers = %v, want %v", got, want)
+			}
+			if got, want := fast.Steps, slow.Steps; got != want {
+				t.Errorf("optimized Steps = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestOut(t *testing.T) {
+	prog := Parse(t, `cpy 3 a
+out a
+dec a
+jnz 1 -2`)
+
+	var got []int
+	m := NewMachine(prog)
+	m.Out = func(v int) bool {
+		got = append(got, v)
+		return len(got) < 5
+	}
+	if m.Run() {
+		t.Errorf("Run() = true, want false (stopped by Out)")
+	}
+	if diff := cmp.Diff(got, []int{3, 2, 1, 0, -1}); diff != "" {
+		t.Errorf("output differs: (-got +want)\n%s", diff)
+	}
+}
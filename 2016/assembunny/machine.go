@@ -0,0 +1,108 @@
+// Copyright 2016 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assembunny
+
+// A Machine executes an assembunny program.
+type Machine struct {
+	// Program is the program being executed.  It is a copy of the program
+	// given to NewMachine, since tgl modifies it.
+	Program   Program
+	Registers [NumRegisters]int // registers a through d
+	PC        int
+	Steps     int // number of instructions executed
+
+	// Optimize enables the peephole optimizer, which executes addition and
+	// multiplication loops in a single step.
+	Optimize bool
+
+	// Out, if set, is called with each value transmitted by out.  If it
+	// returns false, the machine stops after the out instruction.
+	Out func(v int) bool
+}
+
+// NewMachine returns a machine which will run a copy of prog from the
+// beginning, with all registers zero.
+func NewMachine(prog Program) *Machine {
+	return &Machine{
+		Program: append(Program(nil), prog...),
+	}
+}
+
+// Running returns whether the program counter is still within the program.
+func (m *Machine) Running() bool {
+	return m.PC >= 0 && m.PC < len(m.Program)
+}
+
+// Register returns a pointer to the named register, like 'a'.
+func (m *Machine) Register(reg byte) *int {
+	return &m.Registers[reg-'a']
+}
+
+// Value returns the current value of the operand.
+func (m *Machine) Value(o Operand) int {
+	if o.Reg != 0 {
+		return *m.Register(o.Reg)
+	}
+	return o.Value
+}
+
+// Step executes a single instruction (or, if Optimize is set, possibly an
+// entire loop) and reports whether the machine can continue: it returns false
+// if the program has terminated or Out asked it to stop.
+func (m *Machine) Step() bool {
+	if !m.Running() {
+		return false
+	}
+	if m.Optimize && m.fuse() {
+		return true
+	}
+
+	instr, cont := m.Program[m.PC], true
+	m.Steps++
+	if !instr.Valid() {
+		m.PC++
+		return true
+	}
+	switch instr.Op {
+	case Cpy:
+		*m.Register(instr.Y.Reg) = m.Value(instr.X)
+	case Inc:
+		*m.Register(instr.X.Reg)++
+	case Dec:
+		*m.Register(instr.X.Reg)--
+	case Jnz:
+		if m.Value(instr.X) != 0 {
+			m.PC += m.Value(instr.Y) - 1 // offset the increment below
+		}
+	case Tgl:
+		if target := m.PC + m.Value(instr.X); target >= 0 && target < len(m.Program) {
+			m.Program[target].Op = m.Program[target].Op.Toggle()
+		}
+	case Out:
+		if m.Out != nil {
+			cont = m.Out(m.Value(instr.X))
+		}
+	}
+	m.PC++
+	return cont
+}
+
+// Run runs the machine until it stops, and returns whether the program
+// terminated (as opposed to being stopped by Out).
+func (m *Machine) Run() bool {
+	for m.Step() {
+	}
+	return !m.Running()
+}
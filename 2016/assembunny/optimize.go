@@ -0,0 +1,109 @@
+// Copyright 2016 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assembunny
+
+// isImm returns whether the operand is the given immediate.
+func isImm(o Operand, want int) bool {
+	return o.Reg == 0 && o.Value == want
+}
+
+// addLoop matches an addition loop starting at pc:
+//
+//	inc a
+//	dec c
+//	jnz c -2
+//
+// which computes a += c, leaving c zero.  The inc and dec may appear in
+// either order.
+func addLoop(p Program, pc int) (dst, src byte, ok bool) {
+	if pc < 0 || pc+3 > len(p) {
+		return 0, 0, false
+	}
+	inc, dec, jnz := p[pc], p[pc+1], p[pc+2]
+	if inc.Op == Dec {
+		inc, dec = dec, inc
+	}
+	if inc.Op != Inc || dec.Op != Dec || jnz.Op != Jnz {
+		return 0, 0, false
+	}
+	dst, src = inc.X.Reg, dec.X.Reg
+	if dst == 0 || src == 0 || dst == src || jnz.X.Reg != src || !isImm(jnz.Y, -2) {
+		return 0, 0, false
+	}
+	return dst, src, true
+}
+
+// mulLoop matches a multiplication loop starting at pc:
+//
+//	cpy X c
+//	inc a
+//	dec c
+//	jnz c -2
+//	dec d
+//	jnz d -5
+//
+// which computes a += X * d, leaving c and d zero.  The inner loop is any
+// addition loop (see addLoop).
+func mulLoop(p Program, pc int) (dst byte, x Operand, inner, outer byte, ok bool) {
+	if pc < 0 || pc+6 > len(p) {
+		return 0, Operand{}, 0, 0, false
+	}
+	cpy, dec, jnz := p[pc], p[pc+4], p[pc+5]
+	dst, inner, ok = addLoop(p, pc+1)
+	if !ok || cpy.Op != Cpy || dec.Op != Dec || jnz.Op != Jnz {
+		return 0, Operand{}, 0, 0, false
+	}
+	x, outer = cpy.X, dec.X.Reg
+	switch {
+	case cpy.Y.Reg != inner:
+	case outer == 0 || outer == dst || outer == inner:
+	case x.Reg == dst || x.Reg == inner || x.Reg == outer:
+	case jnz.X.Reg != outer || !isImm(jnz.Y, -5):
+	default:
+		return dst, x, inner, outer, true
+	}
+	return 0, Operand{}, 0, 0, false
+}
+
+// fuse executes the loop starting at the current instruction in a single
+// step, if there is one, and reports whether it did so.
+//
+// Loops are matched against the current program each time, so they are no
+// longer fused once tgl modifies them.  Steps is incremented by the number of
+// instructions the loop would have executed, and loops which would not
+// terminate (because a counter starts out non-positive) are not fused.
+func (m *Machine) fuse() bool {
+	if dst, x, inner, outer, ok := mulLoop(m.Program, m.PC); ok {
+		x, n := m.Value(x), m.Registers[outer-'a']
+		if x > 0 && n > 0 {
+			*m.Register(dst) += x * n
+			*m.Register(inner) = 0
+			*m.Register(outer) = 0
+			m.PC += 6
+			m.Steps += n * (3*x + 3)
+			return true
+		}
+	}
+	if dst, src, ok := addLoop(m.Program, m.PC); ok {
+		if n := m.Registers[src-'a']; n > 0 {
+			*m.Register(dst) += n
+			*m.Register(src) = 0
+			m.PC += 3
+			m.Steps += 3 * n
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aocday is the entrypoint for this AoC solution.
+package aocday
+
+import (
+	"testing"
+
+	"github.com/kylelemons/adventofcodesolutions/2016/assembunny"
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+const example = `cpy 41 a
+inc a
+inc a
+dec a
+jnz a 2
+dec a`
+
+func run(t *testing.T, in string, c int) int {
+	m := assembunny.NewMachine(assembunny.Parse(t, in))
+	m.Optimize = true
+	*m.Register('c') = c
+	m.Run()
+	return *m.Register('a')
+}
+
+func part1(t *testing.T, in string) (ret int) {
+	return run(t, in, 0)
+}
+
+func TestPart1(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"part1 example 0", example, 42},
+		{"part1 answer", advent.ReadFile(t, "input.txt"), 318083},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, want := part1(t, test.in), test.want; got != want {
+				t.Errorf("part1(%#v)\n = %#v, want %#v", test.in, got, want)
+			}
+		})
+	}
+}
+
+func part2(t *testing.T, in string) (ret int) {
+	return run(t, in, 1)
+}
+
+func TestPart2(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"part2 answer", advent.ReadFile(t, "input.txt"), 9227737},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, want := part2(t, test.in), test.want; got != want {
+				t.Errorf("part2(%#v)\n = %#v, want %#v", test.in, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aocday is the entrypoint for this AoC solution.
+package aocday
+
+import (
+	"testing"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+	"github.com/kylelemons/adventofcodesolutions/advent/interval"
+)
+
+func allowed(t *testing.T, in string, max int) interval.Set {
+	var blacklist []interval.Interval
+	advent.Lines(in).Extract(t, `(\d+)-(\d+)`, func(lo, hi int) {
+		blacklist = append(blacklist, interval.Closed(lo, hi))
+	})
+	return interval.New(blacklist...).Complement(interval.Closed(0, max))
+}
+
+func part1(t *testing.T, in string, max int) (ret int) {
+	return allowed(t, in, max).Intervals()[0].Lo
+}
+
+func TestPart1(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want int
+	}{
+		{"part1 example 0", "5-8\n0-2\n4-7", 9, 3},
+		{"part1 answer", advent.ReadFile(t, "input.txt"), 4294967295, 32259706},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, want := part1(t, test.in, test.max), test.want; got != want {
+				t.Errorf("part1(%#v)\n = %#v, want %#v", test.in, got, want)
+			}
+		})
+	}
+}
+
+func part2(t *testing.T, in string, max int) (ret int) {
+	return allowed(t, in, max).Len()
+}
+
+func TestPart2(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want int
+	}{
+		{"part2 example 0", "5-8\n0-2\n4-7", 9, 2},
+		{"part2 answer", advent.ReadFile(t, "input.txt"), 4294967295, 113},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, want := part2(t, test.in, test.max), test.want; got != want {
+				t.Errorf("part2(%#v)\n = %#v, want %#v", test.in, got, want)
+			}
+		})
+	}
+}
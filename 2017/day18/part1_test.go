@@ -1,69 +1,17 @@
 package main_test
 
 import (
-	"strconv"
-	"strings"
 	"testing"
-)
-
-func part1(input string) (ret int) {
-	registers := map[string]int{}
-
-	get := func(s string) int {
-		if strings.IndexAny(s, "0123456789") != -1 {
-			v, err := strconv.Atoi(s)
-			if err != nil {
-				panic(err)
-			}
-			return v
-		}
-		return registers[s]
-	}
 
-	commands := strings.Split(input, "\n")
-	var instr, lastPlayed int
-	for count := 0; instr >= 0 && instr < len(commands); count++ {
-		if count > 100000 {
-			panic(count)
-		}
-
-		command := strings.TrimSpace(commands[instr])
-		if command == "" {
-			instr++
-			continue
-		}
-		//fmt.Println(command, registers, lastPlayed)
+	"github.com/kylelemons/adventofcodesolutions/2017/duet"
+)
 
-		args := strings.Fields(commands[instr])
-		switch cmd, argv := args[0], args[1:]; cmd {
-		case "snd":
-			lastPlayed = get(argv[0])
-		case "set":
-			registers[argv[0]] = get(argv[1])
-		case "add":
-			registers[argv[0]] += get(argv[1])
-		case "mul":
-			registers[argv[0]] *= get(argv[1])
-		case "mod":
-			registers[argv[0]] %= get(argv[1])
-		case "rcv":
-			if get(argv[0]) == 0 {
-				break
-			}
-			return lastPlayed
-			// registers[argv[0]] = lastPlayed
-		case "jgz":
-			if get(argv[0]) <= 0 {
-				break
-			}
-			instr += get(argv[1])
-			continue
-		default:
-			panic(command)
-		}
-		instr++
+func part1(t *testing.T, input string) int {
+	sound, ok := duet.Recover(duet.Parse(t, input))
+	if !ok {
+		t.Fatalf("program terminated without recovering a sound")
 	}
-	panic("exit")
+	return sound
 }
 
 func TestPart1(t *testing.T) {
@@ -128,7 +76,7 @@ func TestPart1(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			if got, want := part1(test.in), test.want; got != want {
+			if got, want := part1(t, test.in), test.want; got != want {
 				t.Errorf("part1(%#v) = %#v, want %#v", test.in, got, want)
 			}
 		})
@@ -1,80 +1,17 @@
 package main_test
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
 	"testing"
-	"time"
-)
-
-func part2(input string, id int, out, in chan int) (ret int) {
-	registers := map[string]int{
-		"p": id,
-	}
-
-	get := func(s string) int {
-		if strings.IndexAny(s, "0123456789") != -1 {
-			v, err := strconv.Atoi(s)
-			if err != nil {
-				panic(err)
-			}
-			return v
-		}
-		return registers[s]
-	}
-
-	commands := strings.Split(input, "\n")
-	var instr int
-	for count := 0; instr >= 0 && instr < len(commands); count++ {
-		if count > 100000 {
-			panic(count)
-		}
 
-		command := strings.TrimSpace(commands[instr])
-		if command == "" {
-			instr++
-			continue
-		}
-
-		args := strings.Fields(commands[instr])
-		switch cmd, argv := args[0], args[1:]; cmd {
-		case "set":
-			registers[argv[0]] = get(argv[1])
-		case "add":
-			registers[argv[0]] += get(argv[1])
-		case "mul":
-			registers[argv[0]] *= get(argv[1])
-		case "mod":
-			registers[argv[0]] %= get(argv[1])
-		case "snd":
-			select {
-			case out <- get(argv[0]):
-				fmt.Println("send", get(argv[0]), "from", id)
-				ret++
-			case <-time.After(1 * time.Second):
-				return
-			}
-		case "rcv":
-			select {
-			case registers[argv[0]] = <-in:
-				fmt.Println("got", get(argv[0]), "at", id)
+	"github.com/kylelemons/adventofcodesolutions/2017/duet"
+)
 
-			case <-time.After(1 * time.Second):
-				return
-			}
-		case "jgz":
-			if get(argv[0]) <= 0 {
-				break
-			}
-			instr += get(argv[1])
-			continue
-		default:
-			panic(command)
-		}
-		instr++
+func part2(t *testing.T, input string) int {
+	report := duet.NewScheduler(duet.Parse(t, input), 2).Run()
+	if !report.Deadlock {
+		t.Errorf("programs terminated without deadlocking: %v", report.States)
 	}
-	panic("exit")
+	return report.Sent[1]
 }
 
 func TestPart2(t *testing.T) {
@@ -136,12 +73,7 @@ func TestPart2(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			c01 := make(chan int, 10000)
-			c10 := make(chan int, 10000)
-
-			go part2(test.in, 0, c01, c10)
-
-			if got, want := part2(test.in, 1, c10, c01), test.want; got != want {
+			if got, want := part2(t, test.in), test.want; got != want {
 				t.Errorf("part2(%#v) = %#v, want %#v", test.in, got, want)
 			}
 		})
@@ -4,100 +4,24 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing"
-)
-
-func part1(t *testing.T, in string) string {
-	lines := strings.Split(in, "\n")
 
-	r, c := 0, strings.Index(lines[0], "|")
-
-	deltas := [][2]int{
-		// dr, dc
-		{1, 0},
-		{0, 1},
-		{-1, 0},
-		{0, -1},
-	}
-	dir := 0
+	"github.com/kylelemons/adventofcodesolutions/advent"
+	"github.com/kylelemons/adventofcodesolutions/advent/coords"
+)
 
-	var found string
-	for i := 0; i < 1e6; i++ {
-		ch := lines[r][c]
-		switch ch {
-		default:
-			found += string(ch)
-			fallthrough
-		case '|', '-':
-			r += deltas[dir][0]
-			c += deltas[dir][1]
-		case '+':
-			ld := (dir + 1) % len(deltas)
-			l := deltas[ld]
-			r2, c2 := r+l[0], c+l[1]
-			if lines[r2][c2] != ' ' {
-				dir, r, c = ld, r2, c2
-				continue
-			}
+// trace follows the tubes from the top of the diagram.
+func trace(in string) coords.Path {
+	grid := advent.Split2D(strings.TrimRight(in, "\n"))
+	start := coords.RC(0, strings.IndexByte(string(grid[0]), '|'))
+	return coords.Trace(grid, start, coords.South, func(b byte) bool { return b != ' ' })
+}
 
-			rd := (len(deltas) + dir - 1) % len(deltas)
-			rt := deltas[rd]
-			r2, c2 = r+rt[0], c+rt[1]
-			if lines[r2][c2] != ' ' {
-				dir, r, c = rd, r2, c2
-				continue
-			}
-		case ' ':
-			return found
-		}
-	}
-	panic("timed out")
+func part1(t *testing.T, in string) string {
+	return trace(in).Letters
 }
 
 func part2(t *testing.T, in string) int {
-	lines := strings.Split(in, "\n")
-
-	r, c := 0, strings.Index(lines[0], "|")
-
-	deltas := [][2]int{
-		// dr, dc
-		{1, 0},
-		{0, 1},
-		{-1, 0},
-		{0, -1},
-	}
-	dir := 0
-
-	var found string
-	for i := 0; i < 1e6; i++ {
-		ch := lines[r][c]
-		switch ch {
-		default:
-			found += string(ch)
-			fallthrough
-		case '|', '-':
-			r += deltas[dir][0]
-			c += deltas[dir][1]
-		case '+':
-			ld := (dir + 1) % len(deltas)
-			l := deltas[ld]
-			r2, c2 := r+l[0], c+l[1]
-			if lines[r2][c2] != ' ' {
-				dir, r, c = ld, r2, c2
-				continue
-			}
-
-			rd := (len(deltas) + dir - 1) % len(deltas)
-			rt := deltas[rd]
-			r2, c2 = r+rt[0], c+rt[1]
-			if lines[r2][c2] != ' ' {
-				dir, r, c = rd, r2, c2
-				continue
-			}
-		case ' ':
-			return i
-		}
-	}
-	panic("timed out")
+	return trace(in).Steps + 1 // count the starting square too
 }
 
 func TestPart1(t *testing.T) {
@@ -122,8 +46,30 @@ func TestPart1(t *testing.T) {
 			}
 		})
 	}
+}
 
-	t.Log(part2(t, read(t, "input.txt")))
+func TestPart2(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"part2 example", `     |          
+     |  +--+    
+     A  |  C    
+ F---|----E|--+ 
+     |  |  |  D 
+     +B-+  +--+ `, 38},
+		{"part2", read(t, "input.txt"), 16764},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, want := part2(t, test.in), test.want; got != want {
+				t.Errorf("part2(%#v) = %#v, want %#v", test.in, got, want)
+			}
+		})
+	}
 }
 
 func read(t *testing.T, filename string) string {
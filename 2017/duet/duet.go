@@ -0,0 +1,127 @@
+// Copyright 2017 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package duet implements the 2017 Advent of Code duet assembly language.
+package duet
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// An Op is a duet operation.
+type Op uint8
+
+// The duet operations.  In the descriptions, X and Y are the operands.
+const (
+	Snd Op = iota // sends (or plays) the value of X
+	Set           // X = Y
+	Add           // X += Y
+	Mul           // X *= Y
+	Mod           // X %= Y
+	Rcv           // receives a value into X (or recovers if X != 0)
+	Jgz           // jumps by Y if X > 0
+)
+
+var opNames = [...]string{
+	Snd: "snd",
+	Set: "set",
+	Add: "add",
+	Mul: "mul",
+	Mod: "mod",
+	Rcv: "rcv",
+	Jgz: "jgz",
+}
+
+// String returns the name of the operation, like "snd".
+func (op Op) String() string {
+	if int(op) >= len(opNames) {
+		return fmt.Sprintf("Op(%d)", uint8(op))
+	}
+	return opNames[op]
+}
+
+// An Operand is either a register (named by a single lowercase letter) or an
+// immediate value.
+type Operand struct {
+	Reg   byte // register name, or 0 for an immediate
+	Value int  // immediate value
+}
+
+// String returns the operand in its source form.
+func (o Operand) String() string {
+	if o.Reg != 0 {
+		return string(o.Reg)
+	}
+	return strconv.Itoa(o.Value)
+}
+
+// An Instruction is an operation along with its operands.  Y is unused for
+// snd and rcv.
+type Instruction struct {
+	Op   Op
+	X, Y Operand
+}
+
+// String returns the instruction in its source form, like "add a -1".
+func (i Instruction) String() string {
+	if i.Op == Snd || i.Op == Rcv {
+		return fmt.Sprintf("%v %v", i.Op, i.X)
+	}
+	return fmt.Sprintf("%v %v %v", i.Op, i.X, i.Y)
+}
+
+// A Program is a list of instructions.
+type Program []Instruction
+
+// Parse parses a program in its source form, one instruction per line.
+// Leading and trailing whitespace on each line is ignored.
+func Parse(t advent.OptionalT, source string) Program {
+	var prog Program
+	add := func(op Op, x, y string) {
+		instr := Instruction{Op: op, X: parseOperand(t, x)}
+		if y != "" {
+			instr.Y = parseOperand(t, y)
+		}
+		prog = append(prog, instr)
+	}
+	unary := func(op Op) func(x string) {
+		return func(x string) { add(op, x, "") }
+	}
+	binary := func(op Op) func(x, y string) {
+		return func(x, y string) { add(op, x, y) }
+	}
+	advent.Lines(source).Dispatch(t,
+		advent.On(`^\s*snd (\S+)\s*$`, unary(Snd)),
+		advent.On(`^\s*rcv ([a-z])\s*$`, unary(Rcv)),
+		advent.On(`^\s*set ([a-z]) (\S+)\s*$`, binary(Set)),
+		advent.On(`^\s*add ([a-z]) (\S+)\s*$`, binary(Add)),
+		advent.On(`^\s*mul ([a-z]) (\S+)\s*$`, binary(Mul)),
+		advent.On(`^\s*mod ([a-z]) (\S+)\s*$`, binary(Mod)),
+		advent.On(`^\s*jgz (\S+) (\S+)\s*$`, binary(Jgz)),
+		advent.On(`^\s*$`, func() {}),
+	)
+	return prog
+}
+
+func parseOperand(t advent.OptionalT, s string) Operand {
+	if len(s) == 1 && s[0] >= 'a' && s[0] <= 'z' {
+		return Operand{Reg: s[0]}
+	}
+	var o Operand
+	advent.Scanner(s).Extract(t, `^(-?\d+)$`, &o.Value)
+	return o
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duet
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const soundExample = `set a 1
+add a 2
+mul a a
+mod a 5
+snd a
+set a 0
+rcv a
+jgz a -1
+set a 1
+jgz a -2`
+
+const duetExample = `snd 1
+snd 2
+snd p
+rcv a
+rcv b
+rcv c
+rcv d`
+
+func TestParse(t *testing.T) {
+	prog := Parse(t, "\n\t"+soundExample+"\n")
+	if got, want := len(prog), 10; got != want {
+		t.Fatalf("len(Parse(...)) = %v, want %v", got, want)
+	}
+	if got, want := prog[7], (Instruction{Jgz, Operand{Reg: 'a'}, Operand{Value: -1}}); got != want {
+		t.Errorf("prog[7] = %v, want %v", got, want)
+	}
+
+	var lines []string
+	for _, instr := range prog {
+		lines = append(lines, instr.String())
+	}
+	want := []string{
+		"set a 1", "add a 2", "mul a a", "mod a 5", "snd a",
+		"set a 0", "rcv a", "jgz a -1", "set a 1", "jgz a -2",
+	}
+	if diff := cmp.Diff(lines, want); diff != "" {
+		t.Errorf("String() differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name  string
+		prog  string
+		sound int
+		ok    bool
+	}{
+		{"example", soundExample, 4, true},
+		{"never recovers", "snd 3\nrcv a", 0, false},
+		{"nothing played", "set a 1\nrcv a\nsnd 2\nrcv a", 2, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sound, ok := Recover(Parse(t, test.prog))
+			if sound != test.sound || ok != test.ok {
+				t.Errorf("Recover(%q) = %v, %v, want %v, %v", test.prog, sound, ok, test.sound, test.ok)
+			}
+		})
+	}
+}
+
+func TestStep(t *testing.T) {
+	p := NewProcess(1, Parse(t, duetExample))
+	var sent []int
+	p.Send = func(v int) { sent = append(sent, v) }
+
+	for p.Step() == Running {
+	}
+	if got, want := p.State(), Blocked; got != want {
+		t.Fatalf("State() = %v, want %v", got, want)
+	}
+	if got, want := p.PC, 3; got != want {
+		t.Errorf("PC = %v, want %v", got, want)
+	}
+	if diff := cmp.Diff(sent, []int{1, 2, 1}); diff != "" {
+		t.Errorf("sent differs: (-got +want)\n%s", diff)
+	}
+
+	// A blocked process doesn't move until it has something to receive.
+	if got, want := p.Step(), Blocked; got != want {
+		t.Errorf("Step() while blocked = %v, want %v", got, want)
+	}
+	p.Inbox = append(p.Inbox, 42)
+	if got, want := p.Step(), Blocked; got != want {
+		t.Errorf("Step() after receiving = %v, want %v", got, want)
+	}
+	if got, want := p.Registers['a'-'a'], 42; got != want {
+		t.Errorf("register a = %v, want %v", got, want)
+	}
+	if got, want := p.Steps, 4; got != want {
+		t.Errorf("Steps = %v, want %v", got, want)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	tests := []struct {
+		name string
+		prog string
+		n    int
+		want Report
+	}{
+		{
+			name: "pair deadlocks",
+			prog: duetExample,
+			n:    2,
+			want: Report{
+				Deadlock: true,
+				States:   []State{Blocked, Blocked},
+				Sent:     []int{3, 3},
+			},
+		},
+		{
+			name: "ring deadlocks",
+			prog: duetExample,
+			n:    3,
+			want: Report{
+				Deadlock: true,
+				States:   []State{Blocked, Blocked, Blocked},
+				Sent:     []int{3, 3, 3},
+			},
+		},
+		{
+			name: "one terminates",
+			prog: "jgz p 3\nrcv a\nrcv a\nsnd 1",
+			n:    2,
+			want: Report{
+				Deadlock: true,
+				States:   []State{Blocked, Terminated},
+				Sent:     []int{0, 1},
+			},
+		},
+		{
+			name: "both terminate",
+			prog: "jgz p 2\nsnd 7\nrcv a\nsnd a",
+			n:    2,
+			want: Report{
+				States: []State{Terminated, Terminated},
+				Sent:   []int{2, 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := NewScheduler(Parse(t, test.prog), test.n).Run()
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("Run() differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
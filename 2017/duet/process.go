@@ -0,0 +1,146 @@
+// Copyright 2017 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duet
+
+import (
+	"fmt"
+)
+
+// A State describes whether a Process can make progress.
+type State int
+
+// The states of a Process.
+const (
+	Running    State = iota // ready to execute its next instruction
+	Blocked                 // waiting on rcv with an empty inbox
+	Terminated              // jumped outside the program
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Blocked:
+		return "blocked"
+	case Terminated:
+		return "terminated"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// A Process is a single running copy of a Program.
+type Process struct {
+	ID        int
+	Program   Program
+	PC        int
+	Registers [26]int // registers a through z
+
+	Inbox []int // values sent to this process which it has not yet received
+	Sent  int   // number of values sent by this process
+	Steps int   // number of instructions executed
+
+	// Send is called with each value sent by the process.
+	Send func(v int)
+}
+
+// NewProcess returns a process which will run prog from the beginning with
+// its program ID in register p, as described in the puzzle.
+func NewProcess(id int, prog Program) *Process {
+	p := &Process{
+		ID:      id,
+		Program: prog,
+		Send:    func(int) {},
+	}
+	p.Registers['p'-'a'] = id
+	return p
+}
+
+// Value returns the current value of the operand.
+func (p *Process) Value(o Operand) int {
+	if o.Reg != 0 {
+		return p.Registers[o.Reg-'a']
+	}
+	return o.Value
+}
+
+func (p *Process) register(o Operand) *int {
+	return &p.Registers[o.Reg-'a']
+}
+
+// State returns whether the process can make progress.
+func (p *Process) State() State {
+	switch {
+	case p.PC < 0 || p.PC >= len(p.Program):
+		return Terminated
+	case p.Program[p.PC].Op == Rcv && len(p.Inbox) == 0:
+		return Blocked
+	}
+	return Running
+}
+
+// Step executes a single instruction, unless the process is blocked or has
+// terminated, and returns its state afterward.
+func (p *Process) Step() State {
+	if state := p.State(); state != Running {
+		return state
+	}
+	instr := p.Program[p.PC]
+	switch instr.Op {
+	case Snd:
+		p.Sent++
+		p.Send(p.Value(instr.X))
+	case Set:
+		*p.register(instr.X) = p.Value(instr.Y)
+	case Add:
+		*p.register(instr.X) += p.Value(instr.Y)
+	case Mul:
+		*p.register(instr.X) *= p.Value(instr.Y)
+	case Mod:
+		*p.register(instr.X) %= p.Value(instr.Y)
+	case Rcv:
+		*p.register(instr.X), p.Inbox = p.Inbox[0], p.Inbox[1:]
+	case Jgz:
+		if p.Value(instr.X) > 0 {
+			p.PC += p.Value(instr.Y) - 1 // offset the increment below
+		}
+	}
+	p.PC++
+	p.Steps++
+	return p.State()
+}
+
+// Recover runs prog as described in part 1 of the puzzle, where snd plays a
+// sound and rcv recovers the most recently played sound if its operand is
+// non-zero, and returns the first recovered sound.
+//
+// If the program terminates without recovering a sound, ok is false.
+func Recover(prog Program) (sound int, ok bool) {
+	p := NewProcess(0, prog)
+
+	played := false
+	p.Send = func(v int) { sound, played = v, true }
+	for p.State() != Terminated {
+		if instr := p.Program[p.PC]; instr.Op == Rcv {
+			if p.Value(instr.X) != 0 && played {
+				return sound, true
+			}
+			p.PC++ // rcv is a no-op when it doesn't recover
+			continue
+		}
+		p.Step()
+	}
+	return 0, false
+}
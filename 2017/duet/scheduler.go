@@ -0,0 +1,78 @@
+// Copyright 2017 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duet
+
+// A Scheduler runs several processes cooperatively, on a single goroutine.
+//
+// Since the processes only interact through their inboxes, the order in which
+// they are interleaved does not affect what they send or receive, so the
+// results are deterministic.
+type Scheduler struct {
+	Processes []*Process
+}
+
+// NewScheduler returns a scheduler for n copies of prog, with IDs 0 through
+// n-1, in which each process sends to the next one (wrapping around), so that
+// with two processes they send to each other as described in the puzzle.
+func NewScheduler(prog Program, n int) *Scheduler {
+	s := &Scheduler{}
+	for id := 0; id < n; id++ {
+		s.Processes = append(s.Processes, NewProcess(id, prog))
+	}
+	for id, p := range s.Processes {
+		to := s.Processes[(id+1)%n]
+		p.Send = func(v int) { to.Inbox = append(to.Inbox, v) }
+	}
+	return s
+}
+
+// A Report describes how a run of a Scheduler ended.
+type Report struct {
+	// Deadlock is true if the run ended with at least one process blocked
+	// on rcv, which it can never leave because every other process is also
+	// blocked or has terminated.
+	Deadlock bool
+
+	States []State // final state of each process
+	Sent   []int   // number of values sent by each process
+}
+
+// Run runs every process until none of them can make progress: each has
+// either terminated or is blocked on rcv with an empty inbox.
+//
+// Each process runs until it blocks before the next one gets a turn, which
+// keeps the inboxes full enough to minimize switching.
+func (s *Scheduler) Run() Report {
+	for progress := true; progress; {
+		progress = false
+		for _, p := range s.Processes {
+			for p.State() == Running {
+				p.Step()
+				progress = true
+			}
+		}
+	}
+
+	var r Report
+	for _, p := range s.Processes {
+		state := p.State()
+		if state == Blocked {
+			r.Deadlock = true
+		}
+		r.States = append(r.States, state)
+		r.Sent = append(r.Sent, p.Sent)
+	}
+	return r
+}
@@ -146,7 +146,7 @@ func part2(t *testing.T, in string, limit int) (ret int) {
 		for x := min.x; x <= max.x; x++ {
 			var safety int
 			for _, start := range starts {
-				safety += abs(start.x-x) + abs(start.y-y)
+				safety += advent.Abs(start.x-x) + advent.Abs(start.y-y)
 			}
 			if safety < limit {
 				ret++
@@ -156,13 +156,6 @@ func part2(t *testing.T, in string, limit int) (ret int) {
 	return
 }
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
-
 func TestPart1(t *testing.T) {
 	tests := []struct {
 		name string
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/kylelemons/adventofcodesolutions/advent"
+	"github.com/kylelemons/adventofcodesolutions/advent/ocr"
 )
 
 type point struct {
@@ -52,14 +53,16 @@ func backtrack(points []point) (x, y advent.RangeTracker) {
 	return
 }
 
-func part1(t *testing.T, in string) (ret int) {
+// converge advances the points until they are closest together, returning the
+// number of seconds that took and the image they form.
+func converge(t *testing.T, in string) (seconds int, disp [][]byte) {
 	points := parse(t, in)
 
 	lastX, lastY := advance(points)
-	ret++
+	seconds++
 	for {
 		curX, curY := advance(points)
-		ret++
+		seconds++
 		if curX.Delta() > lastX.Delta() && curY.Delta() > lastY.Delta() {
 			break
 		}
@@ -67,18 +70,27 @@ func part1(t *testing.T, in string) (ret int) {
 	}
 
 	x, y := backtrack(points)
-	ret--
-	disp := advent.Make2D(y.Delta()+1, x.Delta()+1)
+	seconds--
+	disp = advent.Make2D(y.Delta()+1, x.Delta()+1)
 	for _, p := range points {
 		disp[p.y-y.Min][p.x-x.Min] = '#'
 	}
 	for _, row := range disp {
 		t.Logf("%s", strings.ReplaceAll(string(row), "\x00", " "))
 	}
+	return seconds, disp
+}
 
+func part1(t *testing.T, in string) (ret int) {
+	ret, _ = converge(t, in)
 	return
 }
 
+func message(t *testing.T, in string) string {
+	_, disp := converge(t, in)
+	return advent.Must(ocr.Read(disp))(t)
+}
+
 func TestPart1(t *testing.T) {
 	tests := []struct {
 		name string
@@ -129,3 +141,21 @@ position=<-3,  6> velocity=< 2, -1>
 		})
 	}
 }
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"message answer", advent.ReadFile(t, "input.txt"), "AJZNXHKE"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, want := message(t, test.in), test.want; got != want {
+				t.Errorf("message(%#v)\n = %#v, want %#v", test.in, got, want)
+			}
+		})
+	}
+}
@@ -16,118 +16,16 @@
 package acoday
 
 import (
-	"strings"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
+	"github.com/kylelemons/adventofcodesolutions/2018/elfcode"
 	"github.com/kylelemons/adventofcodesolutions/advent"
 )
 
 func part1(t *testing.T, in string) (ret int) {
-	for _, obs := range strings.Split(in, "\n\n") {
-		lines := strings.Split(obs, "\n")
-
-		var r0, r1, r2, r3 int
-		advent.Scanner(lines[0]).Extract(t, `Before: \[(-?\d+), (-?\d+), (-?\d+), (-?\d+)\]`, &r0, &r1, &r2, &r3)
-		before := []int{r0, r1, r2, r3}
-
-		var instr, a, b, dst int
-		advent.Scanner(lines[1]).Scan(t, &instr, &a, &b, &dst)
-
-		advent.Scanner(lines[2]).Extract(t, `After:  \[(-?\d+), (-?\d+), (-?\d+), (-?\d+)\]`, &r0, &r1, &r2, &r3)
-		after := []int{r0, r1, r2, r3}
-
-		var possible []string
-		try := func(instr string, f func([]int)) {
-			regs := make([]int, 4)
-			copy(regs, before)
-			f(regs)
-			if cmp.Equal(regs, after) {
-				possible = append(possible, instr)
-			}
-		}
-
-		try("addr", func(regs []int) {
-			regs[dst] = regs[a] + regs[b]
-		})
-		try("addi", func(regs []int) {
-			regs[dst] = regs[a] + b
-		})
-
-		try("mulr", func(regs []int) {
-			regs[dst] = regs[a] * regs[b]
-		})
-		try("muli", func(regs []int) {
-			regs[dst] = regs[a] * b
-		})
-
-		try("banr", func(regs []int) {
-			regs[dst] = regs[a] & regs[b]
-		})
-		try("bani", func(regs []int) {
-			regs[dst] = regs[a] & b
-		})
-
-		try("borr", func(regs []int) {
-			regs[dst] = regs[a] | regs[b]
-		})
-		try("bori", func(regs []int) {
-			regs[dst] = regs[a] | b
-		})
-
-		try("setr", func(regs []int) {
-			regs[dst] = regs[a]
-		})
-		try("seti", func(regs []int) {
-			regs[dst] = a
-		})
-
-		try("gtir", func(regs []int) {
-			if a > regs[b] {
-				regs[dst] = 1
-			} else {
-				regs[dst] = 0
-			}
-		})
-		try("gtri", func(regs []int) {
-			if regs[a] > b {
-				regs[dst] = 1
-			} else {
-				regs[dst] = 0
-			}
-		})
-		try("gtrr", func(regs []int) {
-			if regs[a] > regs[b] {
-				regs[dst] = 1
-			} else {
-				regs[dst] = 0
-			}
-		})
-
-		try("eqir", func(regs []int) {
-			if a == regs[b] {
-				regs[dst] = 1
-			} else {
-				regs[dst] = 0
-			}
-		})
-		try("eqri", func(regs []int) {
-			if regs[a] == b {
-				regs[dst] = 1
-			} else {
-				regs[dst] = 0
-			}
-		})
-		try("eqrr", func(regs []int) {
-			if regs[a] == regs[b] {
-				regs[dst] = 1
-			} else {
-				regs[dst] = 0
-			}
-		})
-
-		// t.Logf("Instruction %d could be %v", instr, possible)
-		if len(possible) >= 3 {
+	for _, sample := range elfcode.ParseSamples(t, in) {
+		// t.Logf("Instruction %d could be %v", sample.Opcode, sample.Candidates(elfcode.Ops()))
+		if len(sample.Candidates(elfcode.Ops())) >= 3 {
 			ret++
 		}
 	}
@@ -154,162 +52,27 @@ func TestPart1(t *testing.T) {
 }
 
 func part2(t *testing.T, obs, prog string) (ret int) {
-	instructions := make(map[string]func(dst, a, b int, regs []int))
-	addInstr := func(name string, f func(dst, a, b int, regs []int)) {
-		instructions[name] = f
+	inf := elfcode.Infer(elfcode.ParseSamples(t, obs), elfcode.Ops())
+	found, ok := inf.Unique()
+	if !ok {
+		t.Fatalf("Failed to resolve:\n%s", inf)
 	}
-
-	addInstr("addr", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a] + regs[b]
-	})
-	addInstr("addi", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a] + b
-	})
-
-	addInstr("mulr", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a] * regs[b]
-	})
-	addInstr("muli", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a] * b
-	})
-
-	addInstr("banr", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a] & regs[b]
-	})
-	addInstr("bani", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a] & b
-	})
-
-	addInstr("borr", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a] | regs[b]
-	})
-	addInstr("bori", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a] | b
-	})
-
-	addInstr("setr", func(dst, a, b int, regs []int) {
-		regs[dst] = regs[a]
-	})
-	addInstr("seti", func(dst, a, b int, regs []int) {
-		regs[dst] = a
-	})
-
-	addInstr("gtir", func(dst, a, b int, regs []int) {
-		if a > regs[b] {
-			regs[dst] = 1
-		} else {
-			regs[dst] = 0
-		}
-	})
-	addInstr("gtri", func(dst, a, b int, regs []int) {
-		if regs[a] > b {
-			regs[dst] = 1
-		} else {
-			regs[dst] = 0
-		}
-	})
-	addInstr("gtrr", func(dst, a, b int, regs []int) {
-		if regs[a] > regs[b] {
-			regs[dst] = 1
-		} else {
-			regs[dst] = 0
-		}
-	})
-
-	addInstr("eqir", func(dst, a, b int, regs []int) {
-		if a == regs[b] {
-			regs[dst] = 1
-		} else {
-			regs[dst] = 0
-		}
-	})
-	addInstr("eqri", func(dst, a, b int, regs []int) {
-		if regs[a] == b {
-			regs[dst] = 1
-		} else {
-			regs[dst] = 0
-		}
-	})
-	addInstr("eqrr", func(dst, a, b int, regs []int) {
-		if regs[a] == regs[b] {
-			regs[dst] = 1
-		} else {
-			regs[dst] = 0
-		}
-	})
-
-	op := make([]map[string]bool, len(instructions))
-
-	// Mark all possible {opcode, instruction} tuples as possible
-	for opcode := range op {
-		op[opcode] = make(map[string]bool)
-		for instr := range instructions {
-			op[opcode][instr] = true
-		}
-	}
-
-	for _, obs := range strings.Split(obs, "\n\n") {
-		lines := strings.Split(obs, "\n")
-
-		var r0, r1, r2, r3 int
-		advent.Scanner(lines[0]).Extract(t, `Before: \[(-?\d+), (-?\d+), (-?\d+), (-?\d+)\]`, &r0, &r1, &r2, &r3)
-		before := []int{r0, r1, r2, r3}
-
-		var opcode, a, b, dst int
-		advent.Scanner(lines[1]).Scan(t, &opcode, &a, &b, &dst)
-
-		advent.Scanner(lines[2]).Extract(t, `After:  \[(-?\d+), (-?\d+), (-?\d+), (-?\d+)\]`, &r0, &r1, &r2, &r3)
-		after := []int{r0, r1, r2, r3}
-
-		regs := make([]int, 4)
-		for instr := range op[opcode] {
-			copy(regs, before)
-			instructions[instr](dst, a, b, regs)
-			if !cmp.Equal(regs, after) {
-				delete(op[opcode], instr)
-			}
-		}
-	}
-
-	found := make(map[int]string)
-	for {
-		var changes, unresolved int
-		for opcode, possible := range op {
-			if len(possible) > 1 {
-				unresolved++
-				continue
-			}
-			var instr string
-			for instr = range possible {
-			}
-			found[opcode] = instr
-			for other := range op {
-				if opcode != other && op[other][instr] {
-					delete(op[other], instr)
-					changes++
-				}
-			}
-		}
-		t.Logf("Simplify: %d changed, %d unresolved", changes, unresolved)
-		if unresolved == 0 {
-			break
-		}
-		if changes == 0 {
-			t.Fatalf("Stuck: %v", op)
-		}
-	}
-	for opcode := range op {
+	for opcode := 0; opcode < len(found); opcode++ {
 		t.Logf("Opcode %d is %q", opcode, found[opcode])
 	}
-	if len(found) != len(op) {
-		t.Fatalf("Failed to resolve!")
-	}
 
-	regs := make([]int, 4)
+	program := &elfcode.Program{IP: -1}
 	advent.Lines(prog).Scan(t, func(opcode, a, b, dst int) {
-		instructions[found[opcode]](dst, a, b, regs)
+		program.Instructions = append(program.Instructions, elfcode.Instruction{
+			Op: found[opcode],
+			A:  a,
+			B:  b,
+			C:  dst,
+		})
 	})
-	return regs[0]
+	m := elfcode.NewMachine(program, 4)
+	m.Run()
+	return m.Registers[0]
 }
 
 func TestPart2(t *testing.T) {
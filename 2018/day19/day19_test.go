@@ -16,140 +16,29 @@
 package acoday
 
 import (
-	"bytes"
 	"flag"
-	"fmt"
-	"log"
-	"math"
+	"os"
 	"strings"
 	"testing"
-	"time"
 
+	"github.com/kylelemons/adventofcodesolutions/2018/elfcode"
 	"github.com/kylelemons/adventofcodesolutions/advent"
 )
 
 var debug = flag.Bool("debug", false, "If true, print debugging information")
 
-type Computer struct {
-	regs []int
-}
-
-func (c *Computer) addr(dst, a, b int, regs []int) { regs[dst] = regs[a] + regs[b] }
-func (c *Computer) addi(dst, a, b int, regs []int) { regs[dst] = regs[a] + b }
-func (c *Computer) mulr(dst, a, b int, regs []int) { regs[dst] = regs[a] * regs[b] }
-func (c *Computer) muli(dst, a, b int, regs []int) { regs[dst] = regs[a] * b }
-func (c *Computer) banr(dst, a, b int, regs []int) { regs[dst] = regs[a] & regs[b] }
-func (c *Computer) bani(dst, a, b int, regs []int) { regs[dst] = regs[a] & b }
-func (c *Computer) borr(dst, a, b int, regs []int) { regs[dst] = regs[a] | regs[b] }
-func (c *Computer) bori(dst, a, b int, regs []int) { regs[dst] = regs[a] | b }
-func (c *Computer) setr(dst, a, b int, regs []int) { regs[dst] = regs[a] }
-func (c *Computer) seti(dst, a, b int, regs []int) { regs[dst] = a }
-func (c *Computer) gtir(dst, a, b int, regs []int) { regs[dst] = ibool(a > regs[b]) }
-func (c *Computer) gtri(dst, a, b int, regs []int) { regs[dst] = ibool(regs[a] > b) }
-func (c *Computer) gtrr(dst, a, b int, regs []int) { regs[dst] = ibool(regs[a] > regs[b]) }
-func (c *Computer) eqir(dst, a, b int, regs []int) { regs[dst] = ibool(a == regs[b]) }
-func (c *Computer) eqri(dst, a, b int, regs []int) { regs[dst] = ibool(regs[a] == b) }
-func (c *Computer) eqrr(dst, a, b int, regs []int) { regs[dst] = ibool(regs[a] == regs[b]) }
-
-var instr2fun = map[string]func(c *Computer, dst, a, b int, regs []int){
-	"addr": (*Computer).addr,
-	"addi": (*Computer).addi,
-	"mulr": (*Computer).mulr,
-	"muli": (*Computer).muli,
-	"banr": (*Computer).banr,
-	"bani": (*Computer).bani,
-	"borr": (*Computer).borr,
-	"bori": (*Computer).bori,
-	"setr": (*Computer).setr,
-	"seti": (*Computer).seti,
-	"gtir": (*Computer).gtir,
-	"gtri": (*Computer).gtri,
-	"gtrr": (*Computer).gtrr,
-	"eqir": (*Computer).eqir,
-	"eqri": (*Computer).eqri,
-	"eqrr": (*Computer).eqrr,
-}
-
-func (c *Computer) Call(t *testing.T, i Instr) {
-	instr2fun[i.Opcode](c, i.Dst, i.A, i.B, c.regs)
-}
-
-func ibool(b bool) int {
-	if b {
-		return 1
-	}
-	return 0
-}
-
-type Instr struct {
-	Opcode    string
-	A, B, Dst int
-}
-
-type Input struct {
-	Comp Computer
-
-	Registers int
-	PCReg     int
-	Instrs    []Instr
-}
-
-func parseInput(t *testing.T, in string) *Input {
-	input := &Input{
-		Registers: 6,
-		PCReg:     -1,
-	}
-	advent.Lines(in).Each(func(i int, line advent.Scanner) {
-		var instr Instr
-		switch {
-		case line.CanExtract(t, `#ip (\d+)`, &input.PCReg):
-		case line.CanExtract(t, `(\S+) (\d+) (\d+) (\d+)(?:.*#.*)?`, advent.Fields(&instr)...):
-			input.Instrs = append(input.Instrs, instr)
-		default:
-			t.Fatalf("Unrecognized line: %q", line)
-		}
-	})
-	if input.PCReg < 0 {
-		t.Fatalf("No PC Register set in program")
-	}
-	input.Comp.regs = make([]int, input.Registers)
-	return input
-}
-
-func (in *Input) Execute(t *testing.T) {
-	status := time.NewTicker(1 * time.Second)
-	defer status.Stop()
-
-	touched := bytes.Repeat([]byte{'-'}, len(in.Instrs))
-	before := make([]int, len(in.Comp.regs))
-	var ops int
-	for {
-		pc := in.Comp.regs[in.PCReg]
-		if pc >= len(in.Instrs) || pc < 0 {
-			return
-		}
-		copy(before, in.Comp.regs)
-		instr := in.Instrs[pc]
-		touched[pc] = '#'
-		in.Comp.Call(t, instr)
-		in.Comp.regs[in.PCReg]++
-
-		ops++
-		select {
-		case <-status.C:
-			log.Printf("pc=%-3d | instr=%-15s | before=%10v | after=%10v | touched=%s | ops=%v",
-				pc, fmt.Sprint(instr), before, in.Comp.regs, touched, ops)
-			ops = 0
-			touched = bytes.Repeat([]byte{'-'}, len(in.Instrs))
-		default:
-		}
+func execute(prog *elfcode.Program, r0 int) *elfcode.Machine {
+	m := elfcode.NewMachine(prog, elfcode.DefaultRegisters)
+	m.Registers[0] = r0
+	if *debug {
+		m.Trace = os.Stderr
 	}
+	m.Run()
+	return m
 }
 
 func part1(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-	input.Execute(t)
-	return input.Comp.regs[0]
+	return execute(elfcode.Parse(t, in), 0).Registers[0]
 }
 
 func TestPart1(t *testing.T) {
@@ -181,20 +70,24 @@ seti 9 0 5
 }
 
 func part2(t *testing.T, in string) (ret int) {
-	// input := parseInput(t, in)
-	// input.Comp.regs[0] = 1
-	// input.Execute(t)
-	// return input.Comp.regs[0]
-
-	target := 10551424
-	max := int(math.Sqrt(float64(target)) + 1)
-	for factor := 1; factor <= max; factor++ {
-		quo, rem := target/factor, target%factor
-		if rem == 0 {
-			ret += quo + factor
-		}
+	// return execute(elfcode.Parse(t, in), 1).Registers[0]
+
+	prog := elfcode.Parse(t, in)
+	if *debug {
+		t.Logf("Decompiled:\n%s", elfcode.Decompile(prog))
+	}
+
+	// Let the setup code compute the target, and stop when it jumps back
+	// to the start of the (very slow) loops.
+	m := elfcode.NewMachine(prog, elfcode.DefaultRegisters)
+	m.Registers[0] = 1
+	m.Break(1, nil)
+	if stop := m.Run(); stop != elfcode.Breakpoint {
+		t.Fatalf("Run() = %v, want breakpoint", stop)
 	}
-	return
+
+	target := m.Registers[2]
+	return advent.Sum(advent.Divisors(target)...)
 }
 
 /* Notes about part 2:
@@ -218,6 +111,11 @@ Program:
 
 Answer:
 	sum of (factors of 10551424) = 24033240
+
+The loops can be seen with elfcode.Decompile (run with -debug):
+
+	2: // for r1 = 1; r1 <= r2; r1++ (back from 15)
+	3: // for r5 = 1; r5 <= r2; r5++ (back from 11)
 */
 
 func TestPart2(t *testing.T) {
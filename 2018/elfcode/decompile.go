@@ -0,0 +1,388 @@
+// Copyright 2018 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elfcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// A Decompiled program is an elfcode program translated into pseudocode and
+// divided into basic blocks, with jumps through the instruction pointer
+// register shown as gotos.
+type Decompiled struct {
+	Program *Program
+	Lines   []Line  // one per instruction
+	Blocks  []Block // in program order
+	Loops   []Loop  // in order of their back edges
+}
+
+// A Line is the pseudocode for a single instruction.
+type Line struct {
+	PC   int
+	Code string // like "r1 += 1" or "if r4 == r2 goto 7"
+
+	// Next holds the PCs which can execute after this one.  It is empty if
+	// the program halts or Computed is true.
+	Next []int
+
+	// Computed is true if the instruction jumps to an address that depends
+	// on a register which cannot be determined statically.
+	Computed bool
+}
+
+// A Block is a basic block: a sequence of instructions which is only entered
+// at the top and only branches at the bottom.
+type Block struct {
+	Start, End int   // instructions [Start, End)
+	Next       []int // starts of the blocks which can follow this one
+	Computed   bool  // true if the block ends in a computed jump
+}
+
+// A Loop is a backward jump.  If it matches the pattern of a simple counted
+// loop, which increments a counter until it exceeds a limit, the counter and
+// limit are filled in.
+type Loop struct {
+	Head  int // the first instruction in the loop
+	Latch int // the instruction which jumps back to Head
+
+	Counter string // the register incremented by the loop, if counted
+	Init    string // the initial value of Counter, if known
+	Limit   string // the inclusive limit of Counter, if counted
+}
+
+// Counted returns whether the loop is a simple counted loop.
+func (l Loop) Counted() bool {
+	return l.Counter != ""
+}
+
+// String returns a counted loop in C-style pseudocode, like
+// "for r1 = 1; r1 <= r2; r1++", or "loop" for other loops.
+func (l Loop) String() string {
+	if !l.Counted() {
+		return "loop"
+	}
+	init := ""
+	if l.Init != "" {
+		init = fmt.Sprintf(" %s = %s", l.Counter, l.Init)
+	}
+	return fmt.Sprintf("for%s; %s <= %s; %s++", init, l.Counter, l.Limit, l.Counter)
+}
+
+// Decompile translates p into pseudocode.
+//
+// Registers are named r0 through r5.  Reads of the instruction pointer
+// register are replaced by the (constant) address of the instruction, and
+// writes to it become gotos.  Conditional jumps of the form "ip += rX" after a
+// comparison into rX are shown as "if <comparison> goto <address>".
+func Decompile(p *Program) *Decompiled {
+	d := &Decompiled{Program: p}
+	for pc, instr := range p.Instructions {
+		d.Lines = append(d.Lines, d.line(pc, instr))
+	}
+	d.findBlocks()
+	d.findLoops()
+	return d
+}
+
+// operand returns the pseudocode for reading register r at pc.
+func (d *Decompiled) operand(pc, r int) string {
+	if r == d.Program.IP {
+		return fmt.Sprint(pc)
+	}
+	return fmt.Sprintf("r%d", r)
+}
+
+// operands returns the pseudocode for the inputs of instr at pc.
+func (d *Decompiled) operands(pc int, instr Instruction) (a, b string) {
+	a, b = fmt.Sprint(instr.A), fmt.Sprint(instr.B)
+	if instr.Op.RegA() {
+		a = d.operand(pc, instr.A)
+	}
+	if instr.Op.RegB() {
+		b = d.operand(pc, instr.B)
+	}
+	return a, b
+}
+
+// symbol returns the operator for the arithmetic, bitwise and comparison
+// operations, or "" for the set operations.
+func symbol(op Op) string {
+	switch op {
+	case Addr, Addi:
+		return "+"
+	case Mulr, Muli:
+		return "*"
+	case Banr, Bani:
+		return "&"
+	case Borr, Bori:
+		return "|"
+	case Gtir, Gtri, Gtrr:
+		return ">"
+	case Eqir, Eqri, Eqrr:
+		return "=="
+	}
+	return ""
+}
+
+// expr returns the pseudocode for the value computed by instr at pc.
+func (d *Decompiled) expr(pc int, instr Instruction) string {
+	a, b := d.operands(pc, instr)
+	if sym := symbol(instr.Op); sym != "" {
+		return a + " " + sym + " " + b
+	}
+	return a
+}
+
+// constant returns the value computed by instr at pc if it only depends on
+// immediates and the instruction pointer.
+func (d *Decompiled) constant(pc int, instr Instruction) (int, bool) {
+	if (instr.Op.RegA() && instr.A != d.Program.IP) || (instr.Op.RegB() && instr.B != d.Program.IP) {
+		return 0, false
+	}
+	size := advent.Max(DefaultRegisters, instr.C+1, d.Program.IP+1)
+	if instr.Op.RegA() {
+		size = advent.Max(size, instr.A+1)
+	}
+	if instr.Op.RegB() {
+		size = advent.Max(size, instr.B+1)
+	}
+	regs := make([]int, size)
+	if d.Program.IP >= 0 {
+		regs[d.Program.IP] = pc
+	}
+	instr.Apply(regs)
+	return regs[instr.C], true
+}
+
+func (d *Decompiled) line(pc int, instr Instruction) Line {
+	l := Line{PC: pc}
+	inRange := func(target int) bool {
+		return target >= 0 && target < len(d.Program.Instructions)
+	}
+	goTo := func(target int) {
+		if !inRange(target) {
+			l.Code = "halt"
+			return
+		}
+		l.Code = fmt.Sprintf("goto %d", target)
+		l.Next = []int{target}
+	}
+
+	if instr.C != d.Program.IP {
+		a, b := d.operands(pc, instr)
+		switch sym := symbol(instr.Op); {
+		case sym == "" || sym == ">" || sym == "==":
+			l.Code = fmt.Sprintf("r%d = %s", instr.C, d.expr(pc, instr))
+		case instr.Op.RegA() && instr.A == instr.C:
+			// Use compound assignment for things like counters.
+			l.Code = fmt.Sprintf("r%d %s= %s", instr.C, sym, b)
+		case instr.Op.RegB() && instr.B == instr.C:
+			// All of the arithmetic operations are commutative.
+			l.Code = fmt.Sprintf("r%d %s= %s", instr.C, sym, a)
+		default:
+			l.Code = fmt.Sprintf("r%d = %s", instr.C, d.expr(pc, instr))
+		}
+		if inRange(pc + 1) {
+			l.Next = []int{pc + 1}
+		}
+		return l
+	}
+
+	// This instruction writes to the instruction pointer.
+	if v, ok := d.constant(pc, instr); ok {
+		goTo(v + 1)
+		return l
+	}
+
+	// Look for a conditional skip, ip += rX, where rX was just set by a
+	// comparison (and is thus either 0 or 1).
+	if instr.Op == Addr && pc > 0 && (instr.A == d.Program.IP || instr.B == d.Program.IP) {
+		cond := instr.A
+		if cond == d.Program.IP {
+			cond = instr.B
+		}
+		prev := d.Program.Instructions[pc-1]
+		if symbol := symbol(prev.Op); (symbol == ">" || symbol == "==") && prev.C == cond && cond != d.Program.IP {
+			// Show the comparison itself as the condition, unless the
+			// comparison overwrote one of its own inputs.
+			test := d.expr(pc-1, prev)
+			if (prev.Op.RegA() && prev.A == prev.C) || (prev.Op.RegB() && prev.B == prev.C) {
+				test = fmt.Sprintf("r%d", cond)
+			}
+			l.Code = fmt.Sprintf("if %s goto %d", test, pc+2)
+			if !inRange(pc + 2) {
+				l.Code = fmt.Sprintf("if %s halt", test)
+			}
+			for _, next := range []int{pc + 1, pc + 2} {
+				if inRange(next) {
+					l.Next = append(l.Next, next)
+				}
+			}
+			return l
+		}
+	}
+
+	// Otherwise it's a computed jump.
+	l.Computed = true
+	if instr.Op == Addr || instr.Op == Addi {
+		a, b := d.operands(pc, instr)
+		if instr.A == d.Program.IP {
+			a, b = b, a
+		}
+		if b == fmt.Sprint(pc) {
+			l.Code = fmt.Sprintf("goto %d + %s", pc+1, a)
+			return l
+		}
+	}
+	l.Code = fmt.Sprintf("goto (%s) + 1", d.expr(pc, instr))
+	return l
+}
+
+func (d *Decompiled) findBlocks() {
+	leaders := map[int]bool{0: true}
+	for _, l := range d.Lines {
+		if len(l.Next) == 1 && l.Next[0] == l.PC+1 && !l.Computed {
+			continue // straight-line
+		}
+		leaders[l.PC+1] = true
+		for _, next := range l.Next {
+			leaders[next] = true
+		}
+	}
+
+	var starts []int
+	for pc := range leaders {
+		if pc < len(d.Lines) {
+			starts = append(starts, pc)
+		}
+	}
+	sort.Ints(starts)
+	for i, start := range starts {
+		end := len(d.Lines)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		last := d.Lines[end-1]
+		d.Blocks = append(d.Blocks, Block{
+			Start:    start,
+			End:      end,
+			Next:     last.Next,
+			Computed: last.Computed,
+		})
+	}
+}
+
+func (d *Decompiled) findLoops() {
+	instrs := d.Program.Instructions
+	for _, l := range d.Lines {
+		for _, next := range l.Next {
+			if next > l.PC {
+				continue
+			}
+			loop := Loop{Head: next, Latch: l.PC}
+
+			// A counted loop ends with:
+			//
+			//	rX += 1
+			//	rT = rX > rN
+			//	if rT goto <after latch>
+			//	goto <head>
+			if pc := l.PC; pc-3 >= loop.Head {
+				inc, cmp, skip := instrs[pc-3], instrs[pc-2], d.Lines[pc-1]
+				isInc := inc.Op == Addi && inc.A == inc.C && inc.B == 1
+				isCmp := (cmp.Op == Gtrr || cmp.Op == Gtri) && cmp.A == inc.C
+				isSkip := strings.HasPrefix(skip.Code, "if ") && len(skip.Next) > 0 && skip.Next[len(skip.Next)-1] == pc+1
+				if isInc && isCmp && isSkip && len(l.Next) == 1 {
+					loop.Counter = fmt.Sprintf("r%d", inc.C)
+					if cmp.Op == Gtrr {
+						loop.Limit = d.operand(pc-2, cmp.B)
+					} else {
+						loop.Limit = fmt.Sprint(cmp.B)
+					}
+					loop.Init = d.loopInit(loop.Head, inc.C)
+				}
+			}
+			d.Loops = append(d.Loops, loop)
+		}
+	}
+}
+
+// loopInit returns the pseudocode for the value assigned to register r in
+// the straight-line code leading up to head, if it can be found.
+func (d *Decompiled) loopInit(head, r int) string {
+	for pc := head - 1; pc >= 0; pc-- {
+		instr := d.Program.Instructions[pc]
+		if instr.C == r {
+			if instr.Op == Seti || instr.Op == Setr {
+				return d.expr(pc, instr)
+			}
+			return ""
+		}
+		if instr.C == d.Program.IP || d.block(pc).Start == pc {
+			// Don't look past jumps or other ways into this code.
+			return ""
+		}
+	}
+	return ""
+}
+
+// block returns the block containing pc.
+func (d *Decompiled) block(pc int) Block {
+	i := sort.Search(len(d.Blocks), func(i int) bool { return d.Blocks[i].End > pc })
+	return d.Blocks[i]
+}
+
+// String returns the pseudocode for the whole program, with a label at the
+// start of each block that is the target of a jump and a comment for each
+// loop head.
+func (d *Decompiled) String() string {
+	targets := make(map[int]bool)
+	for _, l := range d.Lines {
+		for _, next := range l.Next {
+			if next != l.PC+1 {
+				targets[next] = true
+			}
+		}
+	}
+	loops := make(map[int][]Loop)
+	for _, loop := range d.Loops {
+		loops[loop.Head] = append(loops[loop.Head], loop)
+	}
+
+	var sb strings.Builder
+	if d.Program.IP >= 0 {
+		fmt.Fprintf(&sb, "#ip %d\n", d.Program.IP)
+	}
+	for i, b := range d.Blocks {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if targets[b.Start] || len(loops[b.Start]) > 0 {
+			fmt.Fprintf(&sb, "%d:", b.Start)
+			for _, loop := range loops[b.Start] {
+				fmt.Fprintf(&sb, " // %v (back from %d)", loop, loop.Latch)
+			}
+			sb.WriteString("\n")
+		}
+		for _, l := range d.Lines[b.Start:b.End] {
+			fmt.Fprintf(&sb, "\t%3d  %s\n", l.PC, l.Code)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,158 @@
+// Copyright 2018 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elfcode
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// divisorSum is a program in the style of 2018/day19, which sums the divisors
+// of r2 in the slowest possible way.
+const divisorSum = `#ip 3
+addi 3 16 3
+seti 1 6 1
+seti 1 4 5
+mulr 1 5 4
+eqrr 4 2 4
+addr 4 3 3
+addi 3 1 3
+addr 1 0 0
+addi 5 1 5
+gtrr 5 2 4
+addr 3 4 3
+seti 2 6 3
+addi 1 1 1
+gtrr 1 2 4
+addr 4 3 3
+seti 1 1 3
+mulr 3 3 3
+seti 10 0 2
+addr 3 0 3
+seti 0 0 3
+muli 2 10 2
+seti 0 0 0
+seti 0 0 3`
+
+func TestDecompile(t *testing.T) {
+	d := Decompile(Parse(t, divisorSum))
+
+	want := `#ip 3
+	  0  goto 17
+
+1: // loop (back from 19) // loop (back from 22)
+	  1  r1 = 1
+
+2: // for r1 = 1; r1 <= r2; r1++ (back from 15)
+	  2  r5 = 1
+
+3: // for r5 = 1; r5 <= r2; r5++ (back from 11)
+	  3  r4 = r1 * r5
+	  4  r4 = r4 == r2
+	  5  if r4 goto 7
+
+	  6  goto 8
+
+7:
+	  7  r0 += r1
+
+8:
+	  8  r5 += 1
+	  9  r4 = r5 > r2
+	 10  if r5 > r2 goto 12
+
+	 11  goto 3
+
+12:
+	 12  r1 += 1
+	 13  r4 = r1 > r2
+	 14  if r1 > r2 goto 16
+
+	 15  goto 2
+
+16:
+	 16  halt
+
+17:
+	 17  r2 = 10
+	 18  goto 19 + r0
+
+	 19  goto 1
+
+	 20  r2 *= 10
+	 21  r0 = 0
+	 22  goto 1
+`
+	if diff := cmp.Diff(d.String(), want); diff != "" {
+		t.Errorf("Decompile: (-got +want)\n%s", diff)
+	}
+
+	wantBlocks := []Block{
+		{Start: 0, End: 1, Next: []int{17}},
+		{Start: 1, End: 2, Next: []int{2}},
+		{Start: 2, End: 3, Next: []int{3}},
+		{Start: 3, End: 6, Next: []int{6, 7}},
+		{Start: 6, End: 7, Next: []int{8}},
+		{Start: 7, End: 8, Next: []int{8}},
+		{Start: 8, End: 11, Next: []int{11, 12}},
+		{Start: 11, End: 12, Next: []int{3}},
+		{Start: 12, End: 15, Next: []int{15, 16}},
+		{Start: 15, End: 16, Next: []int{2}},
+		{Start: 16, End: 17},
+		{Start: 17, End: 19, Computed: true},
+		{Start: 19, End: 20, Next: []int{1}},
+		{Start: 20, End: 23, Next: []int{1}},
+	}
+	if diff := cmp.Diff(d.Blocks, wantBlocks); diff != "" {
+		t.Errorf("Blocks: (-got +want)\n%s", diff)
+	}
+
+	wantLoops := []Loop{
+		{Head: 3, Latch: 11, Counter: "r5", Init: "1", Limit: "r2"},
+		{Head: 2, Latch: 15, Counter: "r1", Init: "1", Limit: "r2"},
+		{Head: 1, Latch: 19},
+		{Head: 1, Latch: 22},
+	}
+	if diff := cmp.Diff(d.Loops, wantLoops); diff != "" {
+		t.Errorf("Loops: (-got +want)\n%s", diff)
+	}
+
+	// Make sure the program actually does what the decompiler says.
+	for r0, want := range []int{18, 217} { // divisors of 10 and 100
+		m := NewMachine(Parse(t, divisorSum), DefaultRegisters)
+		m.Registers[0] = r0
+		m.Run()
+		if got := m.Registers[0]; got != want {
+			t.Errorf("r0=%d: result = %v, want %v", r0, got, want)
+		}
+	}
+}
+
+func TestDecompileLargeImmediate(t *testing.T) {
+	// Immediates are not registers, so they must not size the scratch
+	// registers used to evaluate constant jumps.
+	d := Decompile(Parse(t, `#ip 1
+seti 1000000000000 0 2
+seti 1000000000000 0 1`))
+
+	want := `#ip 1
+	  0  r2 = 1000000000000
+	  1  halt
+`
+	if diff := cmp.Diff(d.String(), want); diff != "" {
+		t.Errorf("Decompile: (-got +want)\n%s", diff)
+	}
+}
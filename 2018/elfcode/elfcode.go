@@ -0,0 +1,208 @@
+// Copyright 2018 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package elfcode implements the 2018 Advent of Code register machine, which
+// is used by days 16, 19 and 21.
+package elfcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// An Op is one of the sixteen elfcode operations.
+type Op uint8
+
+// The elfcode operations, in the order they are described in the puzzle.
+//
+// In the descriptions, A and B are the inputs and C is the output register.
+const (
+	Addr Op = iota // C = reg[A] + reg[B]
+	Addi           // C = reg[A] + B
+	Mulr           // C = reg[A] * reg[B]
+	Muli           // C = reg[A] * B
+	Banr           // C = reg[A] & reg[B]
+	Bani           // C = reg[A] & B
+	Borr           // C = reg[A] | reg[B]
+	Bori           // C = reg[A] | B
+	Setr           // C = reg[A]
+	Seti           // C = A
+	Gtir           // C = A > reg[B]
+	Gtri           // C = reg[A] > B
+	Gtrr           // C = reg[A] > reg[B]
+	Eqir           // C = A == reg[B]
+	Eqri           // C = reg[A] == B
+	Eqrr           // C = reg[A] == reg[B]
+
+	// NumOps is the number of operations.
+	NumOps = iota
+)
+
+var opNames = [NumOps]string{
+	"addr", "addi", "mulr", "muli", "banr", "bani", "borr", "bori",
+	"setr", "seti", "gtir", "gtri", "gtrr", "eqir", "eqri", "eqrr",
+}
+
+// Ops returns all of the operations.
+func Ops() []Op {
+	ops := make([]Op, NumOps)
+	for i := range ops {
+		ops[i] = Op(i)
+	}
+	return ops
+}
+
+// ParseOp returns the operation with the given name.
+func ParseOp(name string) (Op, bool) {
+	for i, n := range opNames {
+		if n == name {
+			return Op(i), true
+		}
+	}
+	return 0, false
+}
+
+// String returns the name of the operation, like "addr".
+func (op Op) String() string {
+	if op >= NumOps {
+		return fmt.Sprintf("Op(%d)", uint8(op))
+	}
+	return opNames[op]
+}
+
+// RegA returns whether the operation reads its A input from a register.
+func (op Op) RegA() bool {
+	switch op {
+	case Seti, Gtir, Eqir:
+		return false
+	}
+	return true
+}
+
+// RegB returns whether the operation reads its B input from a register.
+func (op Op) RegB() bool {
+	switch op {
+	case Addr, Mulr, Banr, Borr, Gtir, Gtrr, Eqir, Eqrr:
+		return true
+	}
+	return false
+}
+
+// Apply executes the operation on the registers.
+//
+// Apply will panic if a register index is out of range.
+func (op Op) Apply(regs []int, a, b, c int) {
+	switch op {
+	case Addr:
+		regs[c] = regs[a] + regs[b]
+	case Addi:
+		regs[c] = regs[a] + b
+	case Mulr:
+		regs[c] = regs[a] * regs[b]
+	case Muli:
+		regs[c] = regs[a] * b
+	case Banr:
+		regs[c] = regs[a] & regs[b]
+	case Bani:
+		regs[c] = regs[a] & b
+	case Borr:
+		regs[c] = regs[a] | regs[b]
+	case Bori:
+		regs[c] = regs[a] | b
+	case Setr:
+		regs[c] = regs[a]
+	case Seti:
+		regs[c] = a
+	case Gtir:
+		regs[c] = ibool(a > regs[b])
+	case Gtri:
+		regs[c] = ibool(regs[a] > b)
+	case Gtrr:
+		regs[c] = ibool(regs[a] > regs[b])
+	case Eqir:
+		regs[c] = ibool(a == regs[b])
+	case Eqri:
+		regs[c] = ibool(regs[a] == b)
+	case Eqrr:
+		regs[c] = ibool(regs[a] == regs[b])
+	default:
+		panic(fmt.Sprintf("unknown elfcode operation %v", op))
+	}
+}
+
+func ibool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// An Instruction is an operation along with its operands.
+type Instruction struct {
+	Op      Op
+	A, B, C int
+}
+
+// Apply executes the instruction on the registers.
+func (i Instruction) Apply(regs []int) {
+	i.Op.Apply(regs, i.A, i.B, i.C)
+}
+
+// String returns the instruction in its source form, like "addi 1 2 3".
+func (i Instruction) String() string {
+	return fmt.Sprintf("%v %d %d %d", i.Op, i.A, i.B, i.C)
+}
+
+// A Program is a list of instructions along with the register (if any) to
+// which the instruction pointer is bound.
+type Program struct {
+	IP           int // instruction pointer register, or -1 if unbound
+	Instructions []Instruction
+}
+
+// Parse parses a program in the source form used by the puzzles:
+//
+//	#ip 3
+//	addi 3 16 3
+//	seti 1 0 4
+//
+// The #ip directive is optional.  Anything following a '#' on an instruction
+// line is treated as a comment.  Unknown operations are a fatal error.
+func Parse(t advent.OptionalT, source string) *Program {
+	prog := &Program{IP: -1}
+	advent.Lines(source).Dispatch(t,
+		advent.On(`^#ip (\d+)$`, func(ip int) {
+			prog.IP = ip
+		}),
+		advent.On(`^(`+strings.Join(opNames[:], "|")+`) (-?\d+) (-?\d+) (-?\d+)\s*(?:#.*)?$`, func(name string, a, b, c int) {
+			op, _ := ParseOp(name) // the pattern only matches known names
+			prog.Instructions = append(prog.Instructions, Instruction{Op: op, A: a, B: b, C: c})
+		}),
+	)
+	return prog
+}
+
+// String returns the program in its source form.
+func (p *Program) String() string {
+	var sb strings.Builder
+	if p.IP >= 0 {
+		fmt.Fprintf(&sb, "#ip %d\n", p.IP)
+	}
+	for _, instr := range p.Instructions {
+		fmt.Fprintln(&sb, instr)
+	}
+	return sb.String()
+}
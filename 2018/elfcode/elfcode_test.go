@@ -0,0 +1,123 @@
+// Copyright 2018 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elfcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const example = `#ip 0
+seti 5 0 1
+seti 6 0 2
+addi 0 1 0
+addr 1 2 3
+setr 1 0 0
+seti 8 0 4
+seti 9 0 5`
+
+func TestOps(t *testing.T) {
+	before := []int{3, 2, 1, 1}
+	after := []int{3, 2, 2, 1}
+
+	var matches []Op
+	for _, op := range Ops() {
+		regs := append([]int(nil), before...)
+		op.Apply(regs, 2, 1, 2)
+		if cmp.Equal(regs, after) {
+			matches = append(matches, op)
+		}
+	}
+	if diff := cmp.Diff(matches, []Op{Addi, Mulr, Seti}); diff != "" {
+		t.Errorf("matching ops differ: (-got +want)\n%s", diff)
+	}
+
+	for _, op := range Ops() {
+		if got, ok := ParseOp(op.String()); !ok || got != op {
+			t.Errorf("ParseOp(%q) = %v, %v, want %v, true", op, got, ok, op)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	prog := Parse(t, example+"  # with a comment\n")
+	if got, want := prog.IP, 0; got != want {
+		t.Errorf("IP = %v, want %v", got, want)
+	}
+	if got, want := len(prog.Instructions), 7; got != want {
+		t.Fatalf("len(Instructions) = %v, want %v", got, want)
+	}
+	if got, want := prog.Instructions[3], (Instruction{Op: Addr, A: 1, B: 2, C: 3}); got != want {
+		t.Errorf("Instructions[3] = %v, want %v", got, want)
+	}
+	if got, want := prog.String(), example+"\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestMachine(t *testing.T) {
+	var trace strings.Builder
+	m := NewMachine(Parse(t, example), DefaultRegisters)
+	m.Trace = &trace
+	if got, want := m.Run(), Halted; got != want {
+		t.Errorf("Run() = %v, want %v", got, want)
+	}
+	if got, want := m.Steps, 5; got != want {
+		t.Errorf("Steps = %v, want %v", got, want)
+	}
+
+	want := `ip=0 [0, 0, 0, 0, 0, 0] seti 5 0 1 [0, 5, 0, 0, 0, 0]
+ip=1 [1, 5, 0, 0, 0, 0] seti 6 0 2 [1, 5, 6, 0, 0, 0]
+ip=2 [2, 5, 6, 0, 0, 0] addi 0 1 0 [3, 5, 6, 0, 0, 0]
+ip=4 [4, 5, 6, 0, 0, 0] setr 1 0 0 [5, 5, 6, 0, 0, 0]
+ip=6 [6, 5, 6, 0, 0, 0] seti 9 0 5 [6, 5, 6, 0, 0, 9]
+`
+	if diff := cmp.Diff(trace.String(), want); diff != "" {
+		t.Errorf("trace differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestBreakpoints(t *testing.T) {
+	m := NewMachine(Parse(t, example), DefaultRegisters)
+	m.Break(4, nil)
+
+	var hits []int
+	m.Break(6, func(m *Machine) bool {
+		hits = append(hits, m.Registers[1])
+		return false
+	})
+
+	if got, want := m.Run(), Breakpoint; got != want {
+		t.Fatalf("Run() = %v, want %v", got, want)
+	}
+	if got, want := m.IP, 4; got != want {
+		t.Errorf("IP = %v, want %v", got, want)
+	}
+	if got, want := m.Registers, []int{4, 5, 6, 0, 0, 0}; !cmp.Equal(got, want) {
+		t.Errorf("Registers = %v, want %v", got, want)
+	}
+
+	if got, want := m.Run(), Halted; got != want {
+		t.Fatalf("resumed Run() = %v, want %v", got, want)
+	}
+	if diff := cmp.Diff(hits, []int{5}); diff != "" {
+		t.Errorf("hook calls differ: (-got +want)\n%s", diff)
+	}
+	if m.Step() {
+		t.Errorf("Step() after halting = true, want false")
+	}
+}
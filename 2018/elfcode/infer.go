@@ -0,0 +1,311 @@
+// Copyright 2018 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elfcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// A Sample is an observation of a single instruction, identified only by its
+// opcode number, and the registers before and after it executed.
+type Sample struct {
+	Before  []int
+	Opcode  int
+	A, B, C int
+	After   []int
+}
+
+// ParseSamples parses samples in the format used by 2018/day16:
+//
+//	Before: [3, 2, 1, 1]
+//	9 2 1 2
+//	After:  [3, 2, 2, 1]
+//
+// Samples are separated by blank lines.
+func ParseSamples(t advent.OptionalT, in string) []Sample {
+	var samples []Sample
+	advent.Records(in).Extract(t,
+		`^Before: +\[(-?\d+), (-?\d+), (-?\d+), (-?\d+)\]\n(\d+) (\d+) (\d+) (\d+)\nAfter: +\[(-?\d+), (-?\d+), (-?\d+), (-?\d+)\]$`,
+		func(b0, b1, b2, b3, opcode, a, b, c, a0, a1, a2, a3 int) {
+			samples = append(samples, Sample{
+				Before: []int{b0, b1, b2, b3},
+				Opcode: opcode,
+				A:      a,
+				B:      b,
+				C:      c,
+				After:  []int{a0, a1, a2, a3},
+			})
+		})
+	return samples
+}
+
+// Matches returns whether executing op on the sample's Before registers
+// results in its After registers.
+func (s Sample) Matches(op Op) bool {
+	regs := append([]int(nil), s.Before...)
+
+	// Treating an immediate value as a register can be out of range, which
+	// simply means this operation can't have produced the sample.
+	inRange := func(r int) bool { return r >= 0 && r < len(regs) }
+	if (op.RegA() && !inRange(s.A)) || (op.RegB() && !inRange(s.B)) || !inRange(s.C) {
+		return false
+	}
+	op.Apply(regs, s.A, s.B, s.C)
+	for i := range regs {
+		if regs[i] != s.After[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Candidates returns the operations from ops which match the sample.
+func (s Sample) Candidates(ops []Op) []Op {
+	var matches []Op
+	for _, op := range ops {
+		if s.Matches(op) {
+			matches = append(matches, op)
+		}
+	}
+	return matches
+}
+
+// MaxMappings is the maximum number of mappings that Infer will enumerate when
+// the samples are ambiguous.
+const MaxMappings = 100
+
+// MaxSearchSteps is the maximum number of partial mappings that Infer will
+// consider while enumerating mappings.
+const MaxSearchSteps = 1 << 16
+
+// An Inference is the result of deducing which operation each opcode number
+// corresponds to.
+type Inference struct {
+	// Candidates holds the operations which are consistent with every sample
+	// for each opcode number.
+	Candidates map[int][]Op
+
+	// Mappings holds every assignment of opcode numbers to distinct
+	// operations which is consistent with the samples (up to MaxMappings).
+	Mappings []map[int]Op
+
+	// Truncated is true if the enumeration stopped early, either because
+	// there were more than MaxMappings mappings or because it took more than
+	// MaxSearchSteps steps.
+	Truncated bool
+
+	// Explanation describes, one step per line, how the mappings were
+	// determined and (if there is not exactly one) why.
+	Explanation []string
+}
+
+// Unique returns the mapping if exactly one is consistent with the samples.
+func (inf *Inference) Unique() (map[int]Op, bool) {
+	if len(inf.Mappings) != 1 || inf.Truncated {
+		return nil, false
+	}
+	return inf.Mappings[0], true
+}
+
+// String returns the explanation.
+func (inf *Inference) String() string {
+	return strings.Join(inf.Explanation, "\n")
+}
+
+func (inf *Inference) explainf(format string, args ...interface{}) {
+	inf.Explanation = append(inf.Explanation, fmt.Sprintf(format, args...))
+}
+
+// Infer determines which of ops each opcode number in the samples can be.
+//
+// Each opcode is first restricted to the operations which match all of its
+// samples.  Then, since each operation has a single opcode, the candidates
+// are narrowed down by elimination, and finally any remaining ambiguity is
+// resolved by enumerating every consistent mapping.
+func Infer(samples []Sample, ops []Op) *Inference {
+	inf := &Inference{
+		Candidates: make(map[int][]Op),
+	}
+
+	possible := make(map[int]map[Op]bool) // possible[opcode][op]
+	for i, s := range samples {
+		if possible[s.Opcode] == nil {
+			possible[s.Opcode] = make(map[Op]bool)
+			for _, op := range ops {
+				possible[s.Opcode][op] = true
+			}
+		}
+		if len(possible[s.Opcode]) == 0 {
+			continue // already explained
+		}
+		for op := range possible[s.Opcode] {
+			if !s.Matches(op) {
+				delete(possible[s.Opcode], op)
+			}
+		}
+		if len(possible[s.Opcode]) == 0 {
+			inf.explainf("sample %d rules out the last candidate for opcode %d", i, s.Opcode)
+		}
+	}
+	opcodes := make([]int, 0, len(possible))
+	for opcode := range possible {
+		opcodes = append(opcodes, opcode)
+		inf.Candidates[opcode] = sortedOps(possible[opcode])
+	}
+	sort.Ints(opcodes)
+	for _, opcode := range opcodes {
+		inf.explainf("opcode %d matches %v", opcode, inf.Candidates[opcode])
+	}
+
+	// Eliminate until there's nothing more to learn.
+	for changed := true; changed; {
+		changed = false
+
+		// If an opcode can only be one operation, no other opcode can be.
+		for _, opcode := range opcodes {
+			if len(possible[opcode]) != 1 {
+				continue
+			}
+			only := sortedOps(possible[opcode])[0]
+			for _, other := range opcodes {
+				if other != opcode && possible[other][only] {
+					delete(possible[other], only)
+					inf.explainf("opcode %d cannot be %v, because opcode %d must be", other, only, opcode)
+					changed = true
+				}
+			}
+		}
+
+		// If every operation needs an opcode and an operation can only be
+		// one opcode, that opcode can't be any other operation.
+		if len(opcodes) != len(ops) {
+			continue
+		}
+		for _, op := range ops {
+			var where []int
+			for _, opcode := range opcodes {
+				if possible[opcode][op] {
+					where = append(where, opcode)
+				}
+			}
+			if len(where) != 1 || len(possible[where[0]]) == 1 {
+				continue
+			}
+			opcode := where[0]
+			possible[opcode] = map[Op]bool{op: true}
+			inf.explainf("opcode %d must be %v, because no other opcode can be", opcode, op)
+			changed = true
+		}
+	}
+
+	// Don't bother enumerating if an opcode or a required operation has
+	// nowhere left to go.
+	impossible := false
+	for _, opcode := range opcodes {
+		if len(possible[opcode]) == 0 {
+			impossible = true
+		}
+	}
+	if len(opcodes) == len(ops) {
+		for _, op := range ops {
+			var where []int
+			for _, opcode := range opcodes {
+				if possible[opcode][op] {
+					where = append(where, opcode)
+				}
+			}
+			if len(where) == 0 && !impossible {
+				inf.explainf("no opcode can be %v", op)
+				impossible = true
+			}
+		}
+	}
+
+	// Enumerate the consistent mappings.
+	var (
+		mapping = make(map[int]Op)
+		used    = make(map[Op]bool)
+		steps   int
+		search  func(i int)
+	)
+	search = func(i int) {
+		if inf.Truncated {
+			return
+		}
+		if steps++; len(inf.Mappings) >= MaxMappings || steps > MaxSearchSteps {
+			inf.Truncated = true
+			return
+		}
+		if i == len(opcodes) {
+			found := make(map[int]Op, len(mapping))
+			for k, v := range mapping {
+				found[k] = v
+			}
+			inf.Mappings = append(inf.Mappings, found)
+			return
+		}
+		opcode := opcodes[i]
+		for _, op := range sortedOps(possible[opcode]) {
+			if used[op] {
+				continue
+			}
+			mapping[opcode], used[op] = op, true
+			search(i + 1)
+			delete(mapping, opcode)
+			delete(used, op)
+		}
+	}
+	if !impossible {
+		search(0)
+	}
+
+	switch n := len(inf.Mappings); {
+	case n == 0 && inf.Truncated:
+		inf.explainf("no mapping was found in %d steps", MaxSearchSteps)
+	case n == 0:
+		inf.explainf("no mapping is consistent with the samples")
+	case n == 1 && !inf.Truncated:
+		inf.explainf("the mapping is unique")
+	default:
+		count := fmt.Sprint(n)
+		if inf.Truncated {
+			count = "at least " + count
+		}
+		inf.explainf("%s mappings are consistent with the samples", count)
+		for _, opcode := range opcodes {
+			seen := make(map[Op]bool)
+			for _, m := range inf.Mappings {
+				seen[m[opcode]] = true
+			}
+			if len(seen) > 1 {
+				inf.explainf("opcode %d is ambiguous between %v", opcode, sortedOps(seen))
+			}
+		}
+	}
+	return inf
+}
+
+func sortedOps(set map[Op]bool) []Op {
+	ops := make([]Op, 0, len(set))
+	for op := range set {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i] < ops[j] })
+	return ops
+}
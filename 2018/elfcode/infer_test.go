@@ -0,0 +1,164 @@
+// Copyright 2018 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elfcode
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSamples(t *testing.T) {
+	got := ParseSamples(t, "Before: [3, 2, 1, 1]\n9 2 1 2\nAfter:  [3, 2, 2, 1]\n\nBefore: [0, 1, 2, 3]\n4 0 1 3\nAfter:  [0, 1, 2, 1]\n")
+	want := []Sample{
+		{Before: []int{3, 2, 1, 1}, Opcode: 9, A: 2, B: 1, C: 2, After: []int{3, 2, 2, 1}},
+		{Before: []int{0, 1, 2, 3}, Opcode: 4, A: 0, B: 1, C: 3, After: []int{0, 1, 2, 1}},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("ParseSamples: (-got +want)\n%s", diff)
+	}
+	if diff := cmp.Diff(got[0].Candidates(Ops()), []Op{Addi, Mulr, Seti}); diff != "" {
+		t.Errorf("Candidates: (-got +want)\n%s", diff)
+	}
+}
+
+func TestInferUnique(t *testing.T) {
+	rng := rand.New(rand.NewSource(2018))
+
+	// Scramble the opcodes and generate random samples for each.
+	secret := make(map[int]Op)
+	for opcode, op := range rng.Perm(NumOps) {
+		secret[opcode] = Op(op)
+	}
+	var samples []Sample
+	for i := 0; i < 400; i++ {
+		opcode := rng.Intn(NumOps)
+		s := Sample{
+			Before: []int{rng.Intn(4), rng.Intn(4), rng.Intn(4), rng.Intn(4)},
+			Opcode: opcode,
+			A:      rng.Intn(4),
+			B:      rng.Intn(4),
+			C:      rng.Intn(4),
+		}
+		s.After = append([]int(nil), s.Before...)
+		secret[opcode].Apply(s.After, s.A, s.B, s.C)
+		samples = append(samples, s)
+	}
+
+	inf := Infer(samples, Ops())
+	got, ok := inf.Unique()
+	if !ok {
+		t.Fatalf("Infer did not find a unique mapping:\n%s", inf)
+	}
+	if diff := cmp.Diff(got, secret); diff != "" {
+		t.Errorf("Infer: (-got +want)\n%s", diff)
+	}
+}
+
+func TestInferAmbiguous(t *testing.T) {
+	// Adding 2+2 and multiplying 2*2 are indistinguishable.
+	samples := []Sample{
+		{Before: []int{2, 2, 0, 0}, Opcode: 0, A: 0, B: 1, C: 2, After: []int{2, 2, 4, 0}},
+		{Before: []int{2, 2, 0, 0}, Opcode: 1, A: 0, B: 1, C: 3, After: []int{2, 2, 0, 4}},
+		{Before: []int{7, 0, 0, 0}, Opcode: 2, A: 0, B: 0, C: 1, After: []int{7, 7, 0, 0}},
+	}
+
+	inf := Infer(samples, []Op{Addr, Mulr, Setr})
+	if _, ok := inf.Unique(); ok {
+		t.Errorf("Infer found a unique mapping, want ambiguity:\n%s", inf)
+	}
+	want := []map[int]Op{
+		{0: Addr, 1: Mulr, 2: Setr},
+		{0: Mulr, 1: Addr, 2: Setr},
+	}
+	if diff := cmp.Diff(inf.Mappings, want); diff != "" {
+		t.Errorf("Mappings: (-got +want)\n%s", diff)
+	}
+	if got, want := inf.String(), "opcode 0 is ambiguous between [addr mulr]"; !strings.Contains(got, want) {
+		t.Errorf("Explanation:\n%s\nwant it to contain %q", got, want)
+	}
+	if got, want := inf.String(), "opcode 2 matches [setr]"; !strings.Contains(got, want) {
+		t.Errorf("Explanation:\n%s\nwant it to contain %q", got, want)
+	}
+}
+
+func TestInferInconsistent(t *testing.T) {
+	samples := []Sample{
+		{Before: []int{2, 3, 0, 0}, Opcode: 0, A: 0, B: 1, C: 2, After: []int{2, 3, 5, 0}},
+		{Before: []int{2, 3, 0, 0}, Opcode: 1, A: 0, B: 1, C: 2, After: []int{2, 3, 5, 0}},
+	}
+
+	inf := Infer(samples, Ops())
+	if got := len(inf.Mappings); got != 0 {
+		t.Errorf("Infer found %d mappings, want none:\n%s", got, inf)
+	}
+	if got, want := inf.String(), "no mapping is consistent"; !strings.Contains(got, want) {
+		t.Errorf("Explanation:\n%s\nwant it to contain %q", got, want)
+	}
+}
+
+func TestInferRuledOutOnce(t *testing.T) {
+	// No operation writes register 3 when C is 2, so opcode 0 is ruled out by
+	// the first sample and the rest have nothing left to remove.
+	impossible := Sample{Before: []int{0, 0, 0, 0}, Opcode: 0, A: 0, B: 0, C: 2, After: []int{0, 0, 0, 7}}
+	samples := []Sample{impossible, impossible, impossible}
+
+	inf := Infer(samples, Ops())
+	if got, want := strings.Count(inf.String(), "rules out the last candidate"), 1; got != want {
+		t.Errorf("Explanation:\n%s\nwant %d mention(s) of the last candidate being ruled out, got %d", inf, want, got)
+	}
+	if got, want := inf.String(), "sample 0 rules out the last candidate for opcode 0"; !strings.Contains(got, want) {
+		t.Errorf("Explanation:\n%s\nwant it to contain %q", got, want)
+	}
+}
+
+func TestInferNoCandidates(t *testing.T) {
+	// Every opcode but the last is broad, and nothing matches the last one.
+	var samples []Sample
+	for opcode := 0; opcode < 15; opcode++ {
+		samples = append(samples, Sample{Before: []int{0, 0, 0, 0}, Opcode: opcode, A: 0, B: 0, C: 0, After: []int{0, 0, 0, 0}})
+	}
+	samples = append(samples, Sample{Before: []int{0, 0, 0, 0}, Opcode: 15, A: 0, B: 0, C: 2, After: []int{0, 0, 0, 7}})
+
+	inf := Infer(samples, Ops())
+	if got := len(inf.Mappings); got != 0 || inf.Truncated {
+		t.Errorf("Infer found %d mappings (truncated: %v), want none:\n%s", got, inf.Truncated, inf)
+	}
+	if got, want := inf.String(), "no mapping is consistent"; !strings.Contains(got, want) {
+		t.Errorf("Explanation:\n%s\nwant it to contain %q", got, want)
+	}
+}
+
+func TestInferSearchSteps(t *testing.T) {
+	// Three opcodes can only be addr or addi, but only after all of the
+	// broad opcodes before them have been assigned.
+	var samples []Sample
+	for opcode := 0; opcode < 12; opcode++ {
+		samples = append(samples, Sample{Before: []int{0, 0, 0, 0}, Opcode: opcode, A: 0, B: 0, C: 0, After: []int{0, 0, 0, 0}})
+	}
+	for opcode := 12; opcode < 15; opcode++ {
+		samples = append(samples, Sample{Before: []int{1, 1, 0, 0}, Opcode: opcode, A: 0, B: 1, C: 2, After: []int{1, 1, 2, 0}})
+	}
+
+	inf := Infer(samples, Ops())
+	if got := len(inf.Mappings); got != 0 || !inf.Truncated {
+		t.Errorf("Infer found %d mappings (truncated: %v), want none and truncated:\n%s", got, inf.Truncated, inf)
+	}
+	if got, want := inf.String(), "no mapping was found"; !strings.Contains(got, want) {
+		t.Errorf("Explanation:\n%s\nwant it to contain %q", got, want)
+	}
+}
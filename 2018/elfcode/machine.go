@@ -0,0 +1,160 @@
+// Copyright 2018 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elfcode
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// A Stop describes why a Machine stopped running.
+type Stop int
+
+// The reasons that a Machine can stop.
+const (
+	Halted     Stop = iota // the instruction pointer left the program
+	Breakpoint             // a breakpoint was hit
+)
+
+// String returns the name of the reason.
+func (s Stop) String() string {
+	switch s {
+	case Halted:
+		return "halted"
+	case Breakpoint:
+		return "breakpoint"
+	}
+	return fmt.Sprintf("Stop(%d)", int(s))
+}
+
+// DefaultRegisters is the number of registers used by the 2018/day19 and
+// 2018/day21 machines.
+const DefaultRegisters = 6
+
+// A Machine executes a Program.
+type Machine struct {
+	Program   *Program
+	Registers []int
+	IP        int // instruction pointer
+	Steps     int // number of instructions executed
+
+	// Trace, if non-nil, receives one line per instruction executed in the
+	// same format as the puzzle examples:
+	//
+	//	ip=0 [0, 0, 0, 0, 0, 0] seti 5 0 1 [0, 5, 0, 0, 0, 0]
+	Trace io.Writer
+
+	breakpoints map[int]func(m *Machine) bool
+	paused      bool // stopped at the breakpoint at IP
+}
+
+// NewMachine returns a machine which will run p with the given number of
+// registers, all initially zero.
+func NewMachine(p *Program, registers int) *Machine {
+	if p.IP >= registers {
+		panic(fmt.Sprintf("instruction pointer bound to r%d but only %d registers", p.IP, registers))
+	}
+	return &Machine{
+		Program:   p,
+		Registers: make([]int, registers),
+	}
+}
+
+// Running returns whether the instruction pointer is within the program.
+func (m *Machine) Running() bool {
+	return m.IP >= 0 && m.IP < len(m.Program.Instructions)
+}
+
+// Step executes a single instruction and returns whether the machine is still
+// running.
+func (m *Machine) Step() bool {
+	if !m.Running() {
+		return false
+	}
+	m.paused = false
+	ip, instr := m.IP, m.Program.Instructions[m.IP]
+	bound := m.Program.IP
+	if bound >= 0 {
+		m.Registers[bound] = ip
+	}
+	if m.Trace != nil {
+		fmt.Fprintf(m.Trace, "ip=%d %s %v", ip, m.registers(), instr)
+	}
+
+	instr.Apply(m.Registers)
+	m.Steps++
+
+	if m.Trace != nil {
+		fmt.Fprintf(m.Trace, " %s\n", m.registers())
+	}
+	if bound >= 0 {
+		m.IP = m.Registers[bound]
+	}
+	m.IP++
+	if bound >= 0 {
+		// Keep the register in sync so that it is accurate between steps
+		// (in breakpoint hooks and after halting).
+		m.Registers[bound] = m.IP
+	}
+	return m.Running()
+}
+
+// Break sets a breakpoint before the instruction at ip.
+//
+// If hook is nil, Run stops every time the breakpoint is reached.  Otherwise,
+// hook is called each time the breakpoint is reached and Run only stops if it
+// returns true; hooks may also inspect or modify the machine.
+func (m *Machine) Break(ip int, hook func(m *Machine) bool) {
+	if m.breakpoints == nil {
+		m.breakpoints = make(map[int]func(*Machine) bool)
+	}
+	if hook == nil {
+		hook = func(*Machine) bool { return true }
+	}
+	m.breakpoints[ip] = hook
+}
+
+// ClearBreak removes the breakpoint at ip, if any.
+func (m *Machine) ClearBreak(ip int) {
+	delete(m.breakpoints, ip)
+}
+
+// Run executes instructions until the machine halts or stops at a breakpoint.
+//
+// When Run stops at a breakpoint, the instruction at that location has not
+// yet executed; calling Run again resumes from it without stopping.
+func (m *Machine) Run() Stop {
+	for resume := m.paused; m.Running(); resume = false {
+		if hook, ok := m.breakpoints[m.IP]; ok && !resume && hook(m) {
+			m.paused = true
+			return Breakpoint
+		}
+		m.Step()
+	}
+	m.paused = false
+	return Halted
+}
+
+func (m *Machine) registers() string {
+	b := []byte{'['}
+	for i, r := range m.Registers {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = strconv.AppendInt(b, int64(r), 10)
+	}
+	return string(append(b, ']'))
+}
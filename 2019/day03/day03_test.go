@@ -63,7 +63,7 @@ func part1(t *testing.T, in string) (dist int) {
 					continue
 				}
 				if visited[cur] {
-					manhat := abs(cur.x) + abs(cur.y)
+					manhat := advent.Abs(cur.x) + advent.Abs(cur.y)
 					// t.Logf("Intersect at %v: %d", cur, manhat)
 					if manhat < min {
 						min = manhat
@@ -114,13 +114,6 @@ func part2(t *testing.T, in string) (dist int) {
 	return min
 }
 
-func abs(a int) int {
-	if a < 0 {
-		return -a
-	}
-	return a
-}
-
 func TestPart1(t *testing.T) {
 	tests := []struct {
 		name string
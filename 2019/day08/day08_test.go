@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/kylelemons/adventofcodesolutions/advent"
+	"github.com/kylelemons/adventofcodesolutions/advent/ocr"
 )
 
 type part1in struct {
@@ -90,7 +91,8 @@ type part2in struct {
 }
 
 type part2ret struct {
-	Image []string
+	Image   []string
+	Letters string
 }
 
 func part2(t *testing.T, in part2in) (ret part2ret) {
@@ -125,6 +127,7 @@ func part2(t *testing.T, in part2in) (ret part2ret) {
 	for i := range out {
 		ret.Image = append(ret.Image, string(out[i]))
 	}
+	ret.Letters = advent.Must(ocr.Read(out))(t)
 
 	return
 }
@@ -144,6 +147,7 @@ func TestPart2(t *testing.T) {
 				"#  # #    #  # #    #  # ",
 				"#  # ####  ##  ####  ##  ",
 			},
+			Letters: "HZCZU",
 		}},
 	}
 
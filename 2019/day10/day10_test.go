@@ -49,13 +49,6 @@ func parseInput(t *testing.T, in string) *Input {
 	return input
 }
 
-func abs(i int) int {
-	if i < 0 {
-		return -i
-	}
-	return i
-}
-
 func part1(t *testing.T, in string) (ret int, base Point) {
 	input := parseInput(t, in)
 
@@ -82,7 +75,7 @@ func part1(t *testing.T, in string) (ret int, base Point) {
 			case to.X == from.X:
 				k = Key{negY: dy < 0, slope: "vert"}
 			default:
-				slope := big.NewRat(int64(abs(dy)), int64(abs(dx))).RatString()
+				slope := big.NewRat(int64(advent.Abs(dy)), int64(advent.Abs(dx))).RatString()
 				k = Key{negY: dy < 0, negX: dx < 0, slope: slope}
 			}
 			if canSee[k] {
@@ -179,7 +172,7 @@ func part2(t *testing.T, in string, from Point) (bet Point) {
 		case dx == 0:
 			return Slope{Slope: "vert", NegY: dy < 0}
 		default:
-			slope := big.NewRat(int64(abs(dy)), int64(abs(dx))).RatString()
+			slope := big.NewRat(int64(advent.Abs(dy)), int64(advent.Abs(dx))).RatString()
 			return Slope{Slope: slope, NegY: dy < 0, NegX: dx < 0}
 		}
 	}
@@ -16,7 +16,6 @@
 package acoday
 
 import (
-	"fmt"
 	"strings"
 	"testing"
 
@@ -98,104 +97,27 @@ func part2(t *testing.T, in string) (ret int) {
 
 	field = advent.Split2D(strings.TrimSpace(initialState.String()))
 
-	var cur coords.Coord
-	var dir coords.Vector
-findStart:
-	for r := range field {
-		for c := range field[r] {
-			switch field[r][c] {
-			case '^':
-				dir = coords.North
-			case '<':
-				dir = coords.East
-			case '>':
-				dir = coords.West
-			case 'v':
-				dir = coords.South
-			default:
-				continue
-			}
-			cur = coords.RC(r, c)
-			field[r][c] = '#'
-			break findStart
-		}
-	}
-
-	var spaces int
-	for r := range field {
-		for c := range field[r] {
-			if field[r][c] == '#' {
-				spaces++
-			}
-		}
-	}
-
-	get := func(next coords.Coord) byte {
-		if next.R() < 0 || next.R() >= len(field) || next.C() < 0 || next.C() >= len(field[0]) {
-			return '.'
-		}
-		return field[next.R()][next.C()]
+	cur, dir, ok := coords.FindArrow2D(field)
+	if !ok {
+		t.Fatalf("no robot found")
 	}
+	field[cur.R()][cur.C()] = '#'
 
-	var path []string
-	for {
-		if left := cur.Add(dir.Left()); get(left) == '#' {
-			path = append(path, "L")
-			dir = dir.Left()
-		} else if right := cur.Add(dir.Right()); get(right) == '#' {
-			path = append(path, "R")
-			dir = dir.Right()
-		} else {
-			break
-		}
+	path := coords.Trace(field, cur, dir, func(b byte) bool { return b == '#' }).Tokens()
+	t.Logf("Path: %s", strings.Join(path, ","))
 
-		var steps int
-		for {
-			if forward := cur.Add(dir); get(forward) == '#' {
-				steps++
-				cur = forward
-			} else {
-				path = append(path, fmt.Sprint(steps))
-				break
-			}
-		}
+	// The robot accepts a main routine and three movement functions, each of
+	// which may be at most 20 characters long.
+	compressed, ok := advent.Compress(path, 3, 20)
+	if !ok {
+		t.Fatalf("path cannot be split into movement functions")
 	}
-
-	long := strings.Join(path, ",")
-	t.Logf("Start: %s", long)
-
-	start := 0
-	var funcs []string
-	for len(funcs) < 3 {
-		start += strings.IndexAny(long[start:], "RL0123456789")
-
-		max, at := 0, 0
-		for length := 1; start+length < len(long) && length < 20; length++ {
-			sub := long[start:][:length]
-			if sub[len(sub)-1] == ',' {
-				continue
-			}
-			count := strings.Count(long, sub)
-			reduction := count * len(sub)
-			if reduction > max {
-				max, at = reduction, length
-			}
-		}
-		fname, best := string(rune('A'+len(funcs))), long[start:][:at]
-		long = strings.ReplaceAll(long, best, fname)
-		t.Logf("%s: %s (reduces by %d)", fname, best, max)
-		funcs = append(funcs, best)
-		start += 2
+	routines := compressed.Routines()
+	t.Logf("Main: %s", routines[0])
+	for i, routine := range routines[1:] {
+		t.Logf("%c: %s", 'A'+i, routine)
 	}
-	t.Logf("Main: %s", long)
-
-	pending := strings.Join([]string{
-		long,
-		funcs[0],
-		funcs[1],
-		funcs[2],
-		"n",
-	}, "\n") + "\n"
+	pending := strings.Join(append(routines, "n"), "\n") + "\n"
 
 	prog := intcode.Compile(t, in)
 	prog.Memory[0] = 2
@@ -39,20 +39,14 @@ func parseInput(t *testing.T, in string) *Input {
 	}
 	input.Mid = coords.RC(len(input.Maze), len(input.Maze[0]))
 
-	for r := 0; r < len(input.Maze); r++ {
-		for c := 0; c < len(input.Maze[0]); c++ {
-			if input.Maze[r][c] != '.' {
-				continue
-			}
-			cur := coords.RC(r, c)
-			for _, dir := range coords.Cardinals {
-				loc := cur.Add(dir)
-				ch := input.Maze[loc.R()][loc.C()]
-				if ch >= 'A' && ch <= 'Z' {
-					label := input.portalName(cur, dir)
-					input.Portal[label] = append(input.Portal[label], cur)
-					input.In[loc] = label
-				}
+	for _, cur := range coords.Find2D(input.Maze, '.') {
+		for _, dir := range coords.Cardinals {
+			loc := cur.Add(dir)
+			ch := input.Maze[loc.R()][loc.C()]
+			if ch >= 'A' && ch <= 'Z' {
+				label := input.portalName(cur, dir)
+				input.Portal[label] = append(input.Portal[label], cur)
+				input.In[loc] = label
 			}
 		}
 	}
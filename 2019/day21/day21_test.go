@@ -16,71 +16,51 @@
 package acoday
 
 import (
+	"flag"
 	"strings"
 	"testing"
 
 	"github.com/kylelemons/adventofcodesolutions/2019/intcode"
+	"github.com/kylelemons/adventofcodesolutions/2019/springscript"
 	"github.com/kylelemons/adventofcodesolutions/advent"
 )
 
-type Input struct {
-	prog *intcode.Program
-}
-
-func parseInput(t *testing.T, in string) *Input {
-	input := &Input{
-		prog: intcode.Compile(t, in),
-	}
-	return input
-}
+// run runs the springdroid with the given script, and returns the hull damage
+// it reports (or zero if it fell) along with the rest of its output.
+func run(t *testing.T, in string, script *springscript.Script) (damage int, output string) {
+	prog := intcode.Compile(t, in)
 
-func part1(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	prog := []string{
-		// Jump if A, B, or C is False
-		"NOT A J", // J = !A
-		"NOT B T", // T = !B
-		"OR T J",  // J = !A | !B
-		"NOT C T", // T = !C
-		"OR T J",  // J = !A | !B | !C
-
-		// ALTERNATE:
-		// Don't jump if A and B and C
-		// "OR A J",
-		// "AND B J",
-		// "AND C J",
-		// "NOT J J",
-
-		// Never jump if we'll land in empty space.
-		"AND D J",
-
-		"WALK",
-	}
-	feed := strings.Join(prog, "\n") + "\n"
-
-	input.prog.Input = func() int {
+	feed := script.String()
+	prog.Input = func() int {
 		ch := feed[0]
 		feed = feed[1:]
 		return int(ch)
 	}
 
-	var line []byte
-	input.prog.Output = func(v int) {
-		switch {
-		case v >= 256:
-			ret = v
-		case v == '\n':
-			t.Logf("%s", line)
-			line = nil
-		default:
-			line = append(line, byte(v))
+	var out strings.Builder
+	prog.Output = func(v int) {
+		if v >= 256 {
+			damage = v
+			return
 		}
+		out.WriteByte(byte(v))
 	}
 
-	input.prog.Run(t)
+	prog.Run(t)
+	return damage, out.String()
+}
 
-	return
+func part1(t *testing.T, in string) (ret int) {
+	// Jump if A, B, or C is a hole, but never jump if we'll land in one:
+	//   J = (!A | !B | !C) & D
+	//     = !(A & B & C) & D
+	script := springscript.Compile(t, "!(A & B & C) & D", springscript.Walk)
+
+	damage, output := run(t, in, script)
+	if damage == 0 {
+		t.Fatalf("droid fell:\n%s%s", script, output)
+	}
+	return damage
 }
 
 func TestPart1(t *testing.T) {
@@ -102,8 +82,6 @@ func TestPart1(t *testing.T) {
 }
 
 func part2(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
 	// Should NOT jump:
 	//   @
 	// #####.#.##.#.####
@@ -124,50 +102,15 @@ func part2(t *testing.T, in string) (ret int) {
 	// #####.#.##.#.####
 	//          ABCDEFGHI
 
-	//
-
-	prog := []string{
-		// Only jump if we're not stranding ourselves:
-		"OR E J", // J = E
-		"OR H J", // J = E | H
-
-		// Try to jump if there's a blank in the next 3 spaces:
-		"OR A T",  // T = A
-		"AND B T", // T = A & B
-		"AND C T", // T = A & B & C
-		"NOT T T", // T = !(A & B & C)
-
-		// Combine the above two conditions:
-		"AND T J", // J = (E | H) & !(A & B & C)
-
-		// Never jump if we'll land in a pit:
-		"AND D J",
-		"RUN",
-	}
-	feed := strings.Join(prog, "\n") + "\n"
-
-	input.prog.Input = func() int {
-		ch := feed[0]
-		feed = feed[1:]
-		return int(ch)
-	}
+	// Only jump if we're not stranding ourselves (E | H), if there's a blank in
+	// the next 3 spaces, and never if we'll land in a pit.
+	script := springscript.Compile(t, "(E | H) & !(A & B & C) & D", springscript.Run)
 
-	var line []byte
-	input.prog.Output = func(v int) {
-		switch {
-		case v >= 256:
-			ret = v
-		case v == '\n':
-			t.Logf("%s", line)
-			line = nil
-		default:
-			line = append(line, byte(v))
-		}
+	damage, output := run(t, in, script)
+	if damage == 0 {
+		t.Fatalf("droid fell:\n%s%s", script, output)
 	}
-
-	input.prog.Run(t)
-
-	return
+	return damage
 }
 
 func TestPart2(t *testing.T) {
@@ -188,3 +131,54 @@ func TestPart2(t *testing.T) {
 		})
 	}
 }
+
+// search finds a script by trying scripts which survive every hull the droid
+// has fallen into so far, until one makes it across.
+func search(t *testing.T, in string, mode springscript.Mode, sensors string) (ret int) {
+	var hulls []string
+	for attempt := 0; attempt < 20; attempt++ {
+		script, ok := springscript.Search(t, hulls, mode, sensors, springscript.MaxInstructions)
+		if !ok {
+			t.Fatalf("no script survives %q", hulls)
+		}
+
+		damage, output := run(t, in, script)
+		if damage > 0 {
+			t.Logf("Script:\n%s", script)
+			return damage
+		}
+		hulls = append(hulls, springscript.FailedHulls(output)...)
+		t.Logf("attempt %d: %d instructions, hulls %q", attempt, len(script.Instructions), hulls)
+	}
+	t.Fatalf("no script found after 20 attempts")
+	return 0
+}
+
+var long = flag.Bool("long", false, "Run long tests")
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    springscript.Mode
+		sensors string
+		long    bool
+		want    int
+	}{
+		{"part1 answer", springscript.Walk, "ABCD", false, 19349939},
+		// Searching with all nine sensors takes too long, so this only uses
+		// the ones which matter according to the analysis in part2.
+		{"part2 answer", springscript.Run, "ABCDEH", true, 1142412777},
+	}
+
+	in := advent.ReadFile(t, "input.txt")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.long && !*long {
+				t.Skip("Run with --long to run this test")
+			}
+			if got, want := search(t, in, test.mode, test.sensors), test.want; got != want {
+				t.Errorf("search(%v, %q) = %#v, want %#v", test.mode, test.sensors, got, want)
+			}
+		})
+	}
+}
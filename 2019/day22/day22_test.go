@@ -17,7 +17,6 @@ package acoday
 
 import (
 	"fmt"
-	"math/big"
 	"strings"
 	"testing"
 
@@ -143,12 +142,6 @@ cut -1`, 10, 7, 6},
 	}
 }
 
-func part1linearized(t *testing.T, in string, N, times int64) func(int64) int64 {
-	a, b := linearize(t, in, N)
-	aExp, bExp := expLinear(a, b, times, N)
-	return linearFunc(aExp, bExp, N)
-}
-
 func TestPart1Linearized(t *testing.T) {
 	tests := []struct {
 		name string
@@ -193,16 +186,15 @@ cut -1
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			for _, times := range []int64{1, 2, 7, 13} {
+			for _, times := range []int{1, 2, 7, 13} {
 				t.Run(fmt.Sprintf("x%d", times), func(t *testing.T) {
 					input := strings.Repeat(test.in+"\n", int(times))
 					_, baseline := part1(t, input, test.N, 0)
 
-					part1linearized(t, test.in, int64(test.N), 1)
-					f := part1linearized(t, test.in, int64(test.N), times)
+					f := shuffleMap(t, test.in, test.N).Pow(times)
 					lin := make([]int, len(baseline))
 					for i := range lin {
-						lin[int(f(int64(i)))] = i
+						lin[f.Apply(i)] = i
 					}
 
 					if diff := cmp.Diff(lin, baseline); diff != "" {
@@ -214,212 +206,50 @@ cut -1
 	}
 }
 
-func linearize(t *testing.T, in string, N int64) (stride, offset *big.Int) {
-	// It turns out that it's possible to compute the output index for an input
-	// index using a linear equation:
-	//
-	//   o = (a*i + b) % N
-	//
-	// Each of the operations is a version of this:
-	//
-	//   Operation | Equation       | Coefficients
-	//   --------- | -------------- | ---------------
-	//   deal      | o = -1*i + -1  | a = -1, b = -1
-	//   cut_n     | o =  1*i + N-n | a =  1, b = N-n
-	//   deal_n    | o =  n*i + 0   | a =  n, b = 0
-	//
-	// Luckily, it seems like linear operations like this can be combined before
-	// the modulo is applied.
-	//
-	// The default coefficients are (a,b) = (1,0) of course.
-
-	var (
-		a = big.NewInt(1)
-		b = big.NewInt(0)
-
-		bigN     = big.NewInt(N)
-		bigNsub1 = big.NewInt(N - 1)
-		neg1     = big.NewInt(-1)
-	)
-	_, _ = bigNsub1, neg1
-
+// shuffleMap returns the map from a card's input index to its output index.
+//
+// Each of the operations is a linear congruence of the form o = (a*i + b) % N:
+//
+//	Operation | Equation       | Coefficients
+//	--------- | -------------- | ---------------
+//	deal      | o = -1*i + -1  | a = -1, b = -1
+//	cut_n     | o =  1*i + N-n | a =  1, b = N-n
+//	deal_n    | o =  n*i + 0   | a =  n, b = 0
+//
+// These compose into a single congruence for the whole shuffle.
+func shuffleMap(t *testing.T, in string, N int) advent.Affine {
+	f := advent.IdentityAffine(N)
 	advent.Lines(in).Each(func(i int, line advent.Scanner) {
-		var n int64
+		var n int
 		switch {
 		case line.CanExtract(t, `deal into new stack`):
-			// o = -1*(a*i + b) + -1
-			//   = -a*i + (-b-1)
-			a.Mul(a, neg1)
-			b.Mul(b, neg1).Add(b, neg1)
+			f = f.Then(advent.NewAffine(-1, -1, N))
 		case line.CanExtract(t, `cut (-?\d+)`, &n):
-			// o = 1*(a*i + b) + N-n
-			//   = a*i + b+N-n
-			b.Add(b, big.NewInt(N-n))
+			f = f.Then(advent.NewAffine(1, -n, N))
 		case line.CanExtract(t, `deal with increment (\d+)`, &n):
-			// o = n*(a*i + b) + 0
-			//   = n*a*i + n*b
-			bign := big.NewInt(n)
-			a.Mul(a, bign)
-			b.Mul(b, bign)
+			f = f.Then(advent.NewAffine(n, 0, N))
 		default:
 			t.Fatalf("Unrecognized line %q", line)
 		}
-
-		// Keep the numbers small
-		a.Mod(a, bigN)
-		b.Mod(b, bigN)
-
-		// t.Logf("%s:", line)
-		// t.Logf("  o = %d * i + %d", a, b)
 	})
-	return a, b
-}
-
-func invertLinear(a, b *big.Int, N int64) (aInv, bInv *big.Int) {
-	// We want to derive a function, f_inv, such that:
-	//
-	//   f_inv(a*x + b) = x | mod m
-	//
-	// The resulting function should be another linear congruence:
-	//
-	//   f_inv(i) = a_inv*i + b_inv | mod m
-	//
-	// We can base this on two known fixed points, x = 1 and 0:
-	//
-	//   x = 1  ->  f_inv(a*1+b) = 1  ->  a_inv*a + a_inv*b + b_inv = 1 | mod m
-	//   x = 0  ->  f_inv(a*0+b) = 0  ->            a_inv*b + b_inv = 0 | mod m
-	//
-	// Subtracting the two equations results in this:
-	//
-	//   a_inv*a = 1 | mod m
-	//
-	// This is the form required for using ModInverse:
-	//
-	//   a_inv = modinv(a, m)
-	//
-	// This can then be substituted back into one of our fixed points:
-	//
-	//   b_inv = -a_inv * b | mod m
-	//
-	// Once these are computed, the input index (and thus the numeric value) of
-	// an output index is:
-	//
-	//   f_inv(o) = i = a_inv*o + b | mod m
-
-	bigN := big.NewInt(N)
-	aInv = new(big.Int).ModInverse(a, bigN)
-	bInv = new(big.Int).Set(aInv)
-	bInv.Neg(bInv).Mul(bInv, b).Mod(bInv, bigN)
-	return aInv, bInv
-}
-
-func expLinear(a, b *big.Int, times, N int64) (aExp, bExp *big.Int) {
-	if a.Int64() == 1 {
-		// Special case: a == 1
-		//
-		//   y1 = a*x + b
-		//      = x + b
-		//
-		//   y2 = (x + b) + b
-		//      = x + 2*b
-		//
-		//   y3 = (x + 2*b) + b
-		//      = x + 3*b
-		//
-		//   yn = x + n*b
-		bExp = big.NewInt(times)
-		bExp.Mul(bExp, b)
-		return a, bExp
-	}
-
-	// Now, we need to consider the number of times that we repeat the operation
-	// in sequence:
-	//
-	//   y1 = a*x + b
-	//
-	//   y2 = a*(a*x + b) + b
-	//      = a^2*x + a*b + b
-	//
-	//   y3 = a*(a^2*x + a*b + b) + b
-	//      = a^3*x + a^2*b + a*b + b
-	//
-	// The general pattern, then, looks like this:
-	//
-	//   yn = a^n*x + a^(n-1)*b + a^(n-2)*b + ... + b
-	//      = a^n*x + b*(a^(n-1) + a^(n-2) + ... + a^0)
-	//
-	// Astute observers (i.e. not me) will recognize the infinite sum there:
-	//
-	//    Sum[a^k, {k, 0, n-1}] = (a^n - 1) / (a - 1)
-	//
-	// This allows us to write that as a finite expression:
-	//
-	//    yn = a^n*x + b*( (a^n - 1) / ( a - 1 ) )
-	//
-	// Unfortunately a^n can't be computed directly.
-	// So, if we break it into separate terms:
-	//
-	//    yn = a^n*x + b * (a^n - 1) * (a-1)^-1 | mod m
-	//
-	// We can compute:
-	//   a^n - 1  as exp(a,n,m)-1
-	//   (a-1)^-1 as exp(a-1,m-2,m)
-	//
-	// This is because Fermat's little theorem implies: (for the second term)
-	//   a^-1 = a^(n-2) | mod n
-
-	if advent.GCD(int(N), int(times)) != 1 {
-		panic(fmt.Sprintf("cannot use fast invmod for non-coprime n=%d m=%d", times, N))
-	}
-
-	var (
-		bigN     = big.NewInt(N)
-		bigTimes = big.NewInt(times)
-		neg1     = big.NewInt(-1)
-	)
-
-	aExp = new(big.Int).Exp(a, big.NewInt(times), bigN)
-
-	bExp = new(big.Int).Set(b)
-
-	t1 := new(big.Int).Exp(a, bigTimes, bigN)
-	t1.Add(t1, neg1)
-	bExp.Mul(bExp, t1)
-
-	t2 := new(big.Int).Set(a)
-	t2.Add(t2, neg1)
-	t2.Exp(t2, big.NewInt(N-2), bigN)
-	bExp.Mul(bExp, t2)
-
-	aExp.Mod(aExp, bigN)
-	bExp.Mod(bExp, bigN)
-
-	return
-}
-
-func linearFunc(a, b *big.Int, N int64) func(int64) int64 {
-	v := new(big.Int)
-	bigN := big.NewInt(N)
-	return func(x int64) int64 {
-		return v.SetInt64(x).Mul(a, v).Add(v, b).Mod(v, bigN).Int64()
-	}
+	return f
 }
 
-func part2(t *testing.T, in string, N, times int64) func(int64) int64 {
-	a, b := linearize(t, in, N)
-	aInv, bInv := invertLinear(a, b, N)
-	aExp, bExp := expLinear(aInv, bInv, times, N)
-	return linearFunc(aExp, bExp, N)
+// part2 returns the map from an output index back to the input index (and
+// thus the numeric value) of the card there after shuffling the given number
+// of times.
+func part2(t *testing.T, in string, N, times int) advent.Affine {
+	return shuffleMap(t, in, N).Pow(-times)
 }
 
 func TestPart2(t *testing.T) {
 	tests := []struct {
 		name  string
 		in    string
-		N     int64
-		at    int64
-		times int64
-		want  int64
+		N     int
+		at    int
+		times int
+		want  int
 	}{
 		{"part1 answer", advent.ReadFile(t, "input.txt"), 10007, 6417, 1, 2019},
 		{"part2 answer", advent.ReadFile(t, "input.txt"), 119315717514047, 2020, 101741582076661, 98461321956136},
@@ -428,7 +258,7 @@ func TestPart2(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			f := part2(t, test.in, test.N, test.times)
-			if got, want := f(test.at), test.want; got != want {
+			if got, want := f.Apply(test.at), test.want; got != want {
 				t.Errorf("part2(...): index %v = %#v, want %#v", test.at, got, want)
 			}
 		})
@@ -0,0 +1,433 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package springscript
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// An Expr is a boolean expression over the droid's sensors.
+type Expr struct {
+	Op   byte    // a sensor ('A' through 'I'), or '!', '&' or '|'
+	Args []*Expr // operands of '!' (exactly one), '&' and '|' (two or more)
+}
+
+// ParseExpr parses a boolean expression like "!(A & B & C) & D".
+//
+// The operators are '!', '&' and '|', in decreasing order of precedence, and
+// parentheses may be used for grouping.  Sensors are named by the letters A
+// through I, and whitespace is ignored.
+//
+// Nested operations of the same kind are flattened and double negations are
+// removed, so the result may not print exactly as it was written.
+func ParseExpr(t advent.OptionalT, source string) *Expr {
+	t = advent.MaybeT(t)
+	t.Helper()
+
+	p := &exprParser{t: t, src: strings.Join(strings.Fields(source), "")}
+	e := p.or()
+	if p.pos < len(p.src) {
+		p.fail("unexpected %q", p.src[p.pos])
+	}
+	return e
+}
+
+type exprParser struct {
+	t   advent.OptionalT
+	src string
+	pos int
+}
+
+func (p *exprParser) fail(format string, args ...interface{}) {
+	p.t.Helper()
+	p.t.Fatalf("parsing %q at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *exprParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *exprParser) or() *Expr {
+	return p.binary('|', p.and)
+}
+
+func (p *exprParser) and() *Expr {
+	return p.binary('&', p.unary)
+}
+
+func (p *exprParser) binary(op byte, operand func() *Expr) *Expr {
+	e := &Expr{Op: op}
+	for {
+		arg := operand()
+		if arg.Op == op {
+			e.Args = append(e.Args, arg.Args...)
+		} else {
+			e.Args = append(e.Args, arg)
+		}
+		if p.peek() != op {
+			break
+		}
+		p.pos++
+	}
+	if len(e.Args) == 1 {
+		return e.Args[0]
+	}
+	return e
+}
+
+func (p *exprParser) unary() *Expr {
+	switch c := p.peek(); {
+	case c == '!':
+		p.pos++
+		arg := p.unary()
+		if arg.Op == '!' {
+			return arg.Args[0]
+		}
+		return &Expr{Op: '!', Args: []*Expr{arg}}
+	case c == '(':
+		p.pos++
+		e := p.or()
+		if p.peek() != ')' {
+			p.fail("missing ')'")
+		}
+		p.pos++
+		return e
+	case c >= 'A' && c <= 'I':
+		p.pos++
+		return &Expr{Op: c}
+	case c == 0:
+		p.fail("unexpected end of expression")
+	default:
+		p.fail("unexpected %q", c)
+	}
+	return nil
+}
+
+// Leaf returns whether the expression is a single sensor.
+func (e *Expr) Leaf() bool {
+	return len(e.Args) == 0
+}
+
+// Eval returns the value of the expression for the given sensor readings.
+func (e *Expr) Eval(s Sensors) bool {
+	switch e.Op {
+	case '!':
+		return !e.Args[0].Eval(s)
+	case '&':
+		for _, arg := range e.Args {
+			if !arg.Eval(s) {
+				return false
+			}
+		}
+		return true
+	case '|':
+		for _, arg := range e.Args {
+			if arg.Eval(s) {
+				return true
+			}
+		}
+		return false
+	}
+	return s.Ground(e.Op)
+}
+
+// String returns the expression in source form, with parentheses around
+// every nested operation, like "!(A & B & C) & D".
+func (e *Expr) String() string {
+	switch {
+	case e.Leaf():
+		return string(e.Op)
+	case e.Op == '!':
+		if arg := e.Args[0]; !arg.Leaf() {
+			return "!(" + arg.String() + ")"
+		}
+		return "!" + e.Args[0].String()
+	}
+	var args []string
+	for _, arg := range e.Args {
+		if s := arg.String(); !arg.Leaf() && arg.Op != '!' {
+			args = append(args, "("+s+")")
+		} else {
+			args = append(args, s)
+		}
+	}
+	return strings.Join(args, " "+string(e.Op)+" ")
+}
+
+// lastSensor returns the last sensor used by the expression.
+func (e *Expr) lastSensor() byte {
+	if e.Leaf() {
+		return e.Op
+	}
+	var last byte
+	for _, arg := range e.Args {
+		if s := arg.lastSensor(); s > last {
+			last = s
+		}
+	}
+	return last
+}
+
+// Compile compiles a boolean expression (see ParseExpr) into a script which
+// jumps exactly when the expression is true.
+//
+// The code generator considers every order of evaluation for the operands of
+// each operation, both polarities of every subexpression (rewriting with De
+// Morgan's laws), and using NOT to flip a register in place.  Compile then
+// searches (see Search) for a shorter script with the same truth table, which
+// finds equivalent expressions with a different shape.  The search gives up
+// after CompileSearchStates states, so for expressions over many sensors the
+// result is only the shortest for the expression as written.
+//
+// Compile fails if the expression uses a sensor which is not available in
+// the mode, needs more than the two writable registers, or compiles to more
+// than MaxInstructions instructions.
+func Compile(t advent.OptionalT, expr string, mode Mode) *Script {
+	t = advent.MaybeT(t)
+	t.Helper()
+
+	e := ParseExpr(t, expr)
+	if last := byte('A' + mode.Sensors() - 1); e.lastSensor() > last {
+		t.Fatalf("compiling %q: sensor %c is out of range for %v", expr, e.lastSensor(), mode)
+	}
+	c := &compiler{memo: make(map[intoKey]intoResult)}
+	code, ok := c.into(e, false, Jump, Temp, true, freshJ|freshT)
+	if !ok {
+		t.Fatalf("compiling %q: more than two registers are required", expr)
+	}
+	sensors, want := e.table()
+	if better, ok := search(mode, sensors, len(code)-1, CompileSearchStates, func(jump table) bool { return jump == want }); ok {
+		code = better.Instructions
+	}
+	s := &Script{Mode: mode, Instructions: code}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("compiling %q: %s", expr, err)
+	}
+	return s
+}
+
+// CompileSearchStates is the number of states Compile will consider while
+// searching for a script shorter than the one it generated.
+const CompileSearchStates = 1 << 16
+
+// table returns the sensors used by the expression (like "ABD") and its truth
+// table over them, in the form used by search.
+func (e *Expr) table() (sensors string, tt table) {
+	var used Sensors
+	var walk func(e *Expr)
+	walk = func(e *Expr) {
+		if e.Leaf() {
+			used |= 1 << (e.Op - 'A')
+		}
+		for _, arg := range e.Args {
+			walk(arg)
+		}
+	}
+	walk(e)
+	for i := 0; i < 9; i++ {
+		if used&(1<<i) != 0 {
+			sensors += string(rune('A' + i))
+		}
+	}
+
+	for row := 0; row < 1<<len(sensors); row++ {
+		var s Sensors
+		for i := range sensors {
+			if row&(1<<i) != 0 {
+				s |= 1 << (sensors[i] - 'A')
+			}
+		}
+		if e.Eval(s) {
+			tt[row/64] |= 1 << (row % 64)
+		}
+	}
+	return sensors, tt
+}
+
+// fresh is a set of registers which have not been written, and so are known
+// to be false.
+type fresh uint8
+
+const (
+	freshJ fresh = 1 << iota
+	freshT
+)
+
+func freshBit(reg byte) fresh {
+	if reg == Temp {
+		return freshT
+	}
+	return freshJ
+}
+
+// after returns the registers which are still fresh after code executes.
+func (f fresh) after(code []Instruction) fresh {
+	for _, instr := range code {
+		f &^= freshBit(instr.Y)
+	}
+	return f
+}
+
+func dual(op byte) byte {
+	if op == '&' {
+		return '|'
+	}
+	return '&'
+}
+
+func opFor(op byte) Op {
+	if op == '&' {
+		return And
+	}
+	return Or
+}
+
+// shorter returns whichever of the two options is valid and shorter,
+// preferring the first.
+func shorter(a []Instruction, aok bool, b []Instruction, bok bool) ([]Instruction, bool) {
+	if !bok || (aok && len(a) <= len(b)) {
+		return a, aok
+	}
+	return b, bok
+}
+
+func concat(code ...[]Instruction) []Instruction {
+	var out []Instruction
+	for _, c := range code {
+		out = append(out, c...)
+	}
+	return out
+}
+
+// A compiler memoizes code generation for subexpressions, which would
+// otherwise take time exponential in the depth of the expression.
+//
+// Code returned from the compiler may be shared, and must not be appended to.
+type compiler struct {
+	memo map[intoKey]intoResult
+}
+
+type intoKey struct {
+	e       *Expr
+	neg     bool
+	r, s    byte
+	scratch bool
+	f       fresh
+}
+
+type intoResult struct {
+	code []Instruction
+	ok   bool
+}
+
+// into returns code which leaves e (or !e, if neg is set) in register r.  If
+// scratch is set, the other register s may be overwritten.
+func (c *compiler) into(e *Expr, neg bool, r, s byte, scratch bool, f fresh) ([]Instruction, bool) {
+	for e.Op == '!' {
+		e, neg = e.Args[0], !neg
+	}
+	key := intoKey{e, neg, r, s, scratch, f}
+	if res, ok := c.memo[key]; ok {
+		return res.code, res.ok
+	}
+	code, ok := c.generate(e, neg, r, s, scratch, f)
+	c.memo[key] = intoResult{code, ok}
+	return code, ok
+}
+
+// generate implements into, without memoization.
+func (c *compiler) generate(e *Expr, neg bool, r, s byte, scratch bool, f fresh) ([]Instruction, bool) {
+	if e.Leaf() {
+		if neg {
+			return []Instruction{{Not, e.Op, r}}, true
+		}
+		if f&freshBit(r) != 0 {
+			return []Instruction{{Or, e.Op, r}}, true
+		}
+		return []Instruction{{Not, e.Op, r}, {Not, r, r}}, true
+	}
+
+	code, ok := c.intoDirect(e, neg, r, s, scratch, f)
+	if flip, fok := c.intoDirect(e, !neg, r, s, scratch, f); fok {
+		code, ok = shorter(code, ok, concat(flip, []Instruction{{Not, r, r}}), true)
+	}
+	return code, ok
+}
+
+// intoDirect is like into for an operation, but never flips the result.
+func (c *compiler) intoDirect(e *Expr, neg bool, r, s byte, scratch bool, f fresh) ([]Instruction, bool) {
+	op := e.Op
+	if neg {
+		op = dual(op) // De Morgan: !(a & b) == !a | !b
+	}
+
+	var best []Instruction
+	var found bool
+	for first := range e.Args {
+		code, ok := c.into(e.Args[first], neg, r, s, scratch, f)
+		for i, arg := range e.Args {
+			if i == first || !ok {
+				continue
+			}
+			var more []Instruction
+			more, ok = c.combine(arg, neg, op, r, s, scratch, f.after(code))
+			code = concat(code, more)
+		}
+		best, found = shorter(best, found, code, ok)
+	}
+	return best, found
+}
+
+// combine returns code which replaces register r with r op e (or r op !e, if
+// neg is set).  If scratch is set, the other register s may be overwritten.
+func (c *compiler) combine(e *Expr, neg bool, op byte, r, s byte, scratch bool, f fresh) ([]Instruction, bool) {
+	for e.Op == '!' {
+		e, neg = e.Args[0], !neg
+	}
+
+	// r op !x == !(!r dual x)
+	inverted := func(x byte) []Instruction {
+		return []Instruction{{Not, r, r}, {opFor(dual(op)), x, r}, {Not, r, r}}
+	}
+
+	if e.Leaf() && !neg {
+		return []Instruction{{opFor(op), e.Op, r}}, true
+	}
+	if e.Leaf() {
+		code, ok := inverted(e.Op), true
+		if scratch {
+			code, ok = shorter([]Instruction{{Not, e.Op, s}, {opFor(op), s, r}}, true, code, ok)
+		}
+		return code, ok
+	}
+	if !scratch {
+		return nil, false
+	}
+
+	code, ok := c.into(e, neg, s, r, false, f)
+	if ok {
+		code = concat(code, []Instruction{{opFor(op), s, r}})
+	}
+	if inv, iok := c.into(e, !neg, s, r, false, f); iok {
+		code, ok = shorter(code, ok, concat(inv, inverted(s)), true)
+	}
+	return code, ok
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package springscript
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"!(A & B & C) & D", "!(A & B & C) & D"},
+		{"(E|H)&!(A&B&C)&D", "(E | H) & !(A & B & C) & D"},
+		{"A & (B & C)", "A & B & C"},
+		{"A | B & C", "A | (B & C)"},
+		{"!!A | !!!B", "A | !B"},
+		{"((I))", "I"},
+	}
+
+	for _, test := range tests {
+		if got, want := ParseExpr(t, test.in).String(), test.want; got != want {
+			t.Errorf("ParseExpr(%q) = %q, want %q", test.in, got, want)
+		}
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", `parsing "" at offset 0: unexpected end of expression`},
+		{"A &", `parsing "A&" at offset 2: unexpected end of expression`},
+		{"(A | B", `parsing "(A|B" at offset 4: missing ')'`},
+		{"A J", `parsing "AJ" at offset 1: unexpected 'J'`},
+		{"A & T", `parsing "A&T" at offset 2: unexpected 'T'`},
+	}
+
+	for _, test := range tests {
+		ft := new(fatalT)
+		ft.catch(func() { ParseExpr(ft, test.in) })
+		if got, want := ft.msg, test.want; got != want {
+			t.Errorf("ParseExpr(%q) failed with %q, want %q", test.in, got, want)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		expr string
+		mode Mode
+		want int // instructions
+	}{
+		{"D", Walk, 1},
+		{"!A", Walk, 1},
+		{"A & !B", Walk, 2},
+		{"!(A & B & C) & D", Walk, 5},
+		{"!A | !B | !C", Walk, 4},
+		{"A | B & C", Walk, 3},
+		{"(E | H) & !(A & B & C) & D", Run, 8},
+		{"(A | B) & (C | D) & (E | F)", Run, 9},
+		{"A & B | C & D | E & F | G & H", Run, 13},
+		{"(A | !B) & (C | !D) & (E | !F) & (G | !H) & I", Run, 12},
+
+		// Shorter scripts with a different shape than the expression.
+		{"A & B | A & C", Walk, 3},
+		{"(A | B) & (A | C)", Walk, 3},
+		{"A | !A", Walk, 1},
+		{"A & !A", Walk, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			s := Compile(t, test.expr, test.mode)
+			if got, want := len(s.Instructions), test.want; got != want {
+				t.Errorf("Compile(%q) has %d instructions, want %d:\n%s", test.expr, got, want, s)
+			}
+
+			e := ParseExpr(t, test.expr)
+			for sensors := Sensors(0); sensors < 1<<9; sensors++ {
+				if got, want := s.Jumps(sensors), e.Eval(sensors); got != want {
+					t.Fatalf("Compile(%q).Jumps(%v) = %v, want %v:\n%s", test.expr, sensors, got, want, s)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileScript(t *testing.T) {
+	got := Compile(t, "(E | H) & !(A & B & C) & D", Run).String()
+	want := `OR E J
+OR H J
+OR A T
+AND B T
+AND C T
+NOT T T
+AND T J
+AND D J
+RUN
+`
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Compile(...) differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		mode Mode
+		want string
+	}{
+		{"E", Walk, `compiling "E": sensor E is out of range for WALK`},
+		{"(A | B) & (C | D) | (E | F) & (G | H)", Run, `compiling "(A | B) & (C | D) | (E | F) & (G | H)": more than two registers are required`},
+		{"(A | !B) & (C | !D) & (E | !F) & (G | !H) & (I | !A) & (B | !C)", Run, `compiling "(A | !B) & (C | !D) & (E | !F) & (G | !H) & (I | !A) & (B | !C)": 17 instructions, maximum is 15`},
+	}
+
+	for _, test := range tests {
+		ft := new(fatalT)
+		ft.catch(func() { Compile(ft, test.expr, test.mode) })
+		if got, want := ft.msg, test.want; got != want {
+			t.Errorf("Compile(%q) failed with %q, want %q", test.expr, got, want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package springscript
+
+import (
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// FailedHulls returns the distinct hulls which appear in the droid's output
+// after it falls into a hole, in the order in which they first appear.
+//
+// The output consists of frames separated by blank lines, each of which ends
+// with the hull: a row of '#' and '.' tiles with the droid starting on the
+// first tile.
+func FailedHulls(output string) []string {
+	var hulls []string
+	seen := make(map[string]bool)
+	for _, frame := range strings.Split(output, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(frame), "\n")
+		hull := strings.TrimSpace(lines[len(lines)-1])
+		if strings.Trim(hull, "#.") != "" || !strings.Contains(hull, "#") || seen[hull] {
+			continue
+		}
+		seen[hull] = true
+		hulls = append(hulls, hull)
+	}
+	return hulls
+}
+
+// A table is a truth table over up to nine sensors: bit i is the value when
+// the sensors read the bits of i.
+type table [8]uint64
+
+func (tt table) get(row int) bool {
+	return tt[row/64]&(1<<(row%64)) != 0
+}
+
+// searchState is the contents of the writable registers, as a function of the
+// sensor readings, in terms of indices into the tables seen by the search.
+type searchState struct {
+	T, J int32
+}
+
+// Search returns a shortest script which survives every hull, reading only
+// the given sensors (like "ABCD"), and with at most maxLen instructions.
+//
+// Search is a breadth-first search over the functions which the script can
+// compute in T and J.  The number of these grows quickly with the number of
+// sensors and instructions, so it is best to start small.
+//
+// If no script is found, ok is false.
+func Search(t advent.OptionalT, hulls []string, mode Mode, sensors string, maxLen int) (script *Script, ok bool) {
+	t = advent.MaybeT(t)
+	t.Helper()
+
+	last := byte('A' + mode.Sensors() - 1)
+	for _, sensor := range []byte(sensors) {
+		if sensor < 'A' || sensor > last {
+			t.Fatalf("sensor %c is out of range for %v", sensor, mode)
+		}
+	}
+
+	project := func(s Sensors) (row int) {
+		for i := range sensors {
+			if s.Ground(sensors[i]) {
+				row |= 1 << i
+			}
+		}
+		return row
+	}
+	survives := func(jump table) bool {
+		for _, hull := range hulls {
+			if _, ok := Simulate(hull, func(s Sensors) bool { return jump.get(project(s)) }); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	return search(mode, sensors, maxLen, 0, survives)
+}
+
+// search returns a shortest script, reading only the given sensors and with at
+// most maxLen instructions, whose jump function is accepted.  Row i of the
+// tables passed to accept is the value when the sensors read the bits of i.
+//
+// If maxStates is positive, the search gives up once it has seen that many
+// states.
+func search(mode Mode, sensors string, maxLen, maxStates int, accept func(jump table) bool) (script *Script, ok bool) {
+	if maxLen > MaxInstructions {
+		maxLen = MaxInstructions
+	}
+
+	// Tables are interned, which keeps the search states small.
+	var tables []table
+	ids := make(map[table]int32)
+	intern := func(tt table) int32 {
+		id, ok := ids[tt]
+		if !ok {
+			id = int32(len(tables))
+			tables = append(tables, tt)
+			ids[tt] = id
+		}
+		return id
+	}
+
+	// The inputs are the sensors, followed by T and J.
+	rows := 1 << len(sensors)
+	inputs := make([]table, len(sensors)+2)
+	var mask table
+	for row := 0; row < rows; row++ {
+		mask[row/64] |= 1 << (row % 64)
+		for i := range sensors {
+			if row&(1<<i) != 0 {
+				inputs[i][row/64] |= 1 << (row % 64)
+			}
+		}
+	}
+
+	// Check each distinct jump function only once.
+	checked := make(map[int32]bool)
+	accepts := func(jump int32) bool {
+		if ok, done := checked[jump]; done {
+			return ok
+		}
+		ok := accept(tables[jump])
+		checked[jump] = ok
+		return ok
+	}
+
+	type step struct {
+		prev  searchState
+		instr Instruction
+	}
+	start := searchState{intern(table{}), intern(table{})}
+	from := map[searchState]step{start: {}}
+	found := func(state searchState) *Script {
+		var code []Instruction
+		for state != start {
+			step := from[state]
+			code = append([]Instruction{step.instr}, code...)
+			state = step.prev
+		}
+		return &Script{Mode: mode, Instructions: code}
+	}
+	if accepts(start.J) {
+		return found(start), true
+	}
+
+	sources := []byte(sensors + string(Temp) + string(Jump))
+	regT, regJ := len(sensors), len(sensors)+1
+	frontier := []searchState{start}
+	for length := 1; length <= maxLen && len(frontier) > 0; length++ {
+		var next []searchState
+		for _, state := range frontier {
+			inputs[regT], inputs[regJ] = tables[state.T], tables[state.J]
+			for x, src := range sources {
+				for _, y := range []int{regT, regJ} {
+					for _, op := range []Op{And, Or, Not} {
+						in, out := &inputs[x], inputs[y]
+						for i := range out {
+							switch op {
+							case And:
+								out[i] &= in[i]
+							case Or:
+								out[i] |= in[i]
+							case Not:
+								out[i] = ^in[i] & mask[i]
+							}
+						}
+						succ := state
+						if y == regT {
+							succ.T = intern(out)
+						} else {
+							succ.J = intern(out)
+						}
+						if _, seen := from[succ]; seen {
+							continue
+						}
+						from[succ] = step{state, Instruction{op, src, sources[y]}}
+						if accepts(succ.J) {
+							return found(succ), true
+						}
+						if maxStates > 0 && len(from) >= maxStates {
+							return nil, false
+						}
+						next = append(next, succ)
+					}
+				}
+			}
+		}
+		frontier = next
+	}
+	return nil, false
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package springscript
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const failure = `Input instructions:
+
+Walking...
+
+
+Didn't make it across:
+
+.................
+.................
+@................
+#####.#..########
+
+.................
+.................
+.@...............
+#####.#..########
+
+.................
+.................
+..@..............
+#####.#..########
+
+...@.............
+..#.#............
+.#...#...........
+#####.#..########
+`
+
+func TestFailedHulls(t *testing.T) {
+	got := FailedHulls(failure + failure + "\n#####.##.#.#..###\n")
+	want := []string{"#####.#..########", "#####.##.#.#..###"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("FailedHulls differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		hulls   []string
+		mode    Mode
+		sensors string
+		maxLen  int
+		want    int // instructions, or -1 if none are found
+	}{
+		{"no hulls", nil, Walk, "ABCD", 15, 0},
+		{"one hole", []string{"#####.###########"}, Walk, "ABCD", 15, 1},
+		{"walk", []string{"#####.###########", "#####.#..########", "#####...#########", "#####..#.########"}, Walk, "ABCD", 15, 4},
+		{"too short", []string{"#####.###########", "#####.#..########", "#####...#########"}, Walk, "ABCD", 1, -1},
+		{"impossible", []string{"#####....########"}, Walk, "ABCD", 3, -1},
+		{"run", []string{"#####.#.##.#.####", "#####..###.#..###", "#####...#########"}, Run, "ABCDEH", 15, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, ok := Search(t, test.hulls, test.mode, test.sensors, test.maxLen)
+			if !ok {
+				if test.want >= 0 {
+					t.Fatalf("Search(...) found nothing, want %d instructions", test.want)
+				}
+				return
+			}
+			if got, want := len(s.Instructions), test.want; got != want {
+				t.Errorf("Search(...) found %d instructions, want %d:\n%s", got, want, s)
+			}
+			if got, want := s.Mode, test.mode; got != want {
+				t.Errorf("Search(...).Mode = %v, want %v", got, want)
+			}
+			for _, hull := range test.hulls {
+				if !s.Survives(hull) {
+					t.Errorf("Search(...) does not survive %q:\n%s", hull, s)
+				}
+			}
+		})
+	}
+}
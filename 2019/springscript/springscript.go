@@ -0,0 +1,270 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package springscript implements the 2019 Advent of Code springdroid
+// language from day 21, along with a local simulation of the droid so that
+// scripts can be checked without running the intcode program.
+package springscript
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// MaxInstructions is the maximum number of instructions the springdroid will
+// accept in a script, not counting WALK or RUN.
+const MaxInstructions = 15
+
+// An Op is a springscript operation.
+type Op uint8
+
+// The springscript operations.  In the descriptions, X and Y are the operands.
+const (
+	And Op = iota // Y = X && Y
+	Or            // Y = X || Y
+	Not           // Y = !X
+)
+
+var opNames = [...]string{
+	And: "AND",
+	Or:  "OR",
+	Not: "NOT",
+}
+
+// String returns the name of the operation, like "AND".
+func (op Op) String() string {
+	if int(op) >= len(opNames) {
+		return fmt.Sprintf("Op(%d)", uint8(op))
+	}
+	return opNames[op]
+}
+
+// The writable registers.  Both are false when a script begins, and the
+// droid jumps if J is true when the script ends.
+const (
+	Temp = 'T'
+	Jump = 'J'
+)
+
+// An Instruction is an operation along with its operands.  X is a register
+// or a sensor (A through I), and Y is a writable register.
+type Instruction struct {
+	Op   Op
+	X, Y byte
+}
+
+// String returns the instruction in its source form, like "NOT A J".
+func (i Instruction) String() string {
+	return fmt.Sprintf("%v %c %c", i.Op, i.X, i.Y)
+}
+
+// A Mode is the command which starts the droid, and determines its range.
+type Mode uint8
+
+// The springdroid modes.
+const (
+	Walk Mode = iota // sensors A through D
+	Run              // sensors A through I
+)
+
+// String returns the command for the mode, like "WALK".
+func (m Mode) String() string {
+	if m == Run {
+		return "RUN"
+	}
+	return "WALK"
+}
+
+// Sensors returns the number of sensors available in the mode.
+func (m Mode) Sensors() int {
+	if m == Run {
+		return 9
+	}
+	return 4
+}
+
+// Sensors is a reading from the droid's sensors: bit i is set if sensor
+// 'A'+i sees ground.
+type Sensors uint16
+
+// Ground returns whether the given sensor, like 'A', sees ground.
+func (s Sensors) Ground(sensor byte) bool {
+	return s&(1<<(sensor-'A')) != 0
+}
+
+// String returns the tiles seen by the sensors, like "#.##.####".
+func (s Sensors) String() string {
+	var sb strings.Builder
+	for i := 0; i < 9; i++ {
+		if s&(1<<i) != 0 {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	return sb.String()
+}
+
+// Sense returns the sensor readings for a droid standing at x on the hull.
+//
+// The hull is a row of '#' (ground) and '.' (hole) tiles, as it appears in
+// the droid's failure output.  Everything beyond the end is ground.
+func Sense(hull string, x int) Sensors {
+	var s Sensors
+	for i := 0; i < 9; i++ {
+		if pos := x + 1 + i; pos >= len(hull) || hull[pos] == '#' {
+			s |= 1 << i
+		}
+	}
+	return s
+}
+
+// A Script is a springscript program.
+type Script struct {
+	Mode         Mode
+	Instructions []Instruction
+}
+
+// Parse parses a script in its source form, one instruction per line and
+// ending with WALK or RUN, as it would be sent to the droid.
+func Parse(t advent.OptionalT, source string) *Script {
+	t = advent.MaybeT(t)
+	t.Helper()
+
+	s := new(Script)
+	done := false
+	advent.Lines(source).Dispatch(t,
+		advent.On(`^\s*(AND|OR|NOT) ([A-IJT]) ([JT])\s*$`, func(op, x, y string) {
+			if done {
+				t.Fatalf("instruction %s %s %s after %v", op, x, y, s.Mode)
+			}
+			instr := Instruction{X: x[0], Y: y[0]}
+			switch op {
+			case "AND":
+				instr.Op = And
+			case "OR":
+				instr.Op = Or
+			case "NOT":
+				instr.Op = Not
+			}
+			s.Instructions = append(s.Instructions, instr)
+		}),
+		advent.On(`^\s*(WALK|RUN)\s*$`, func(mode string) {
+			if done {
+				t.Fatalf("duplicate %s", mode)
+			}
+			if mode == "RUN" {
+				s.Mode = Run
+			}
+			done = true
+		}),
+		advent.On(`^\s*$`, func() {}),
+	)
+	if !done {
+		t.Fatalf("script does not end with WALK or RUN")
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("invalid script: %s", err)
+	}
+	return s
+}
+
+// Validate returns an error if the droid would reject the script, because it
+// is too long or reads a sensor which is out of range for its mode.
+func (s *Script) Validate() error {
+	if got, max := len(s.Instructions), MaxInstructions; got > max {
+		return fmt.Errorf("%d instructions, maximum is %d", got, max)
+	}
+	last := byte('A' + s.Mode.Sensors() - 1)
+	for i, instr := range s.Instructions {
+		if instr.X != Temp && instr.X != Jump && (instr.X < 'A' || instr.X > last) {
+			return fmt.Errorf("instruction %d (%v): sensor %c is out of range for %v", i, instr, instr.X, s.Mode)
+		}
+		if instr.Y != Temp && instr.Y != Jump {
+			return fmt.Errorf("instruction %d (%v): %c is not writable", i, instr, instr.Y)
+		}
+	}
+	return nil
+}
+
+// String returns the script in its source form, ending with WALK or RUN and
+// a newline, suitable for sending to the droid.
+func (s *Script) String() string {
+	var sb strings.Builder
+	for _, instr := range s.Instructions {
+		fmt.Fprintln(&sb, instr)
+	}
+	fmt.Fprintln(&sb, s.Mode)
+	return sb.String()
+}
+
+// Jumps returns whether the droid jumps with the given sensor readings.
+func (s *Script) Jumps(sensors Sensors) bool {
+	var t, j bool
+	reg := func(r byte) *bool {
+		if r == Temp {
+			return &t
+		}
+		return &j
+	}
+	for _, instr := range s.Instructions {
+		var x bool
+		switch instr.X {
+		case Temp, Jump:
+			x = *reg(instr.X)
+		default:
+			x = sensors.Ground(instr.X)
+		}
+		y := reg(instr.Y)
+		switch instr.Op {
+		case And:
+			*y = x && *y
+		case Or:
+			*y = x || *y
+		case Not:
+			*y = !x
+		}
+	}
+	return j
+}
+
+// JumpDistance is the number of tiles the droid moves forward when it jumps.
+const JumpDistance = 4
+
+// Simulate simulates the droid crossing the hull, starting from the first
+// tile, and returns the positions at which it touched down.  If the droid
+// falls into a hole, the last position is the hole and ok is false.
+func Simulate(hull string, jumps func(Sensors) bool) (landed []int, ok bool) {
+	for x := 0; x < len(hull); {
+		if jumps(Sense(hull, x)) {
+			x += JumpDistance
+		} else {
+			x++
+		}
+		landed = append(landed, x)
+		if x < len(hull) && hull[x] != '#' {
+			return landed, false
+		}
+	}
+	return landed, true
+}
+
+// Survives returns whether the droid makes it across the hull when running
+// the script.
+func (s *Script) Survives(hull string) bool {
+	_, ok := Simulate(hull, s.Jumps)
+	return ok
+}
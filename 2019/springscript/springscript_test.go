@@ -0,0 +1,146 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package springscript
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const walk = `NOT A J
+NOT B T
+OR T J
+NOT C T
+OR T J
+AND D J
+WALK
+`
+
+type fatalT struct {
+	msg string
+}
+
+type fatal struct{}
+
+func (t *fatalT) Helper() {}
+
+func (t *fatalT) Fatalf(format string, args ...interface{}) {
+	t.msg = fmt.Sprintf(format, args...)
+	panic(fatal{})
+}
+
+func (t *fatalT) catch(f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(fatal); !ok {
+				panic(r)
+			}
+		}
+	}()
+	f()
+}
+
+func TestParse(t *testing.T) {
+	s := Parse(t, walk)
+	if got, want := s.Mode, Walk; got != want {
+		t.Errorf("Mode = %v, want %v", got, want)
+	}
+	if got, want := s.Instructions[2], (Instruction{Or, 'T', 'J'}); got != want {
+		t.Errorf("Instructions[2] = %v, want %v", got, want)
+	}
+	if diff := cmp.Diff(s.String(), walk); diff != "" {
+		t.Errorf("String() differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestNilT(t *testing.T) {
+	// A nil OptionalT is allowed everywhere, as it is in the advent package.
+	if got, want := Parse(nil, walk).String(), walk; got != want {
+		t.Errorf("Parse(nil, ...) = %q, want %q", got, want)
+	}
+	if got, want := ParseExpr(nil, "!(A & B & C) & D").String(), "!(A & B & C) & D"; got != want {
+		t.Errorf("ParseExpr(nil, ...) = %q, want %q", got, want)
+	}
+	if got, want := len(Compile(nil, "!(A & B & C) & D", Walk).Instructions), 5; got != want {
+		t.Errorf("Compile(nil, ...) has %d instructions, want %d", got, want)
+	}
+	if _, ok := Search(nil, []string{"#####.###########"}, Walk, "ABCD", 15); !ok {
+		t.Errorf("Search(nil, ...) found nothing")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no mode", "NOT A J", "script does not end with WALK or RUN"},
+		{"after mode", "RUN\nNOT A J", "instruction NOT A J after RUN"},
+		{"out of range", "NOT E J\nWALK", "invalid script: instruction 0 (NOT E J): sensor E is out of range for WALK"},
+		{"too long", strings.Repeat("NOT A J\n", 16) + "WALK", "invalid script: 16 instructions, maximum is 15"},
+		{"unwritable", "NOT A B\nWALK", `line 1: no rule matches "NOT A B"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ft := new(fatalT)
+			ft.catch(func() { Parse(ft, test.in) })
+			if got, want := ft.msg, test.want; got != want {
+				t.Errorf("Parse(%q) failed with %q, want %q", test.in, got, want)
+			}
+		})
+	}
+}
+
+func TestSense(t *testing.T) {
+	hull := "#####.#..########"
+	if got, want := Sense(hull, 4).String(), ".#..#####"; got != want {
+		t.Errorf("Sense(%q, 4) = %q, want %q", hull, got, want)
+	}
+	if got, want := Sense(hull, 12).String(), "#########"; got != want {
+		t.Errorf("Sense(%q, 12) = %q, want %q", hull, got, want)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		script string
+		hull   string
+		landed []int
+		ok     bool
+	}{
+		{walk, "#####.#..########", []int{1, 2, 6, 10, 11, 12, 13, 14, 15, 16, 17}, true},
+		{walk, "#####..#.########", []int{1, 2, 3, 7, 11, 12, 13, 14, 15, 16, 17}, true},
+		{walk, "#####.#.##.#.####", []int{1, 2, 6, 7}, false}, // needs RUN
+		{"WALK", "#####.####", []int{1, 2, 3, 4, 5}, false},
+		{"OR D J\nWALK", "###.#.####", []int{4, 8, 12}, true},
+		{"OR D J\nWALK", "#.##.#####", []int{1}, false},
+	}
+
+	for _, test := range tests {
+		s := Parse(t, test.script)
+		landed, ok := Simulate(test.hull, s.Jumps)
+		if diff := cmp.Diff(landed, test.landed); diff != "" || ok != test.ok {
+			t.Errorf("Simulate(%q) = ..., %v, want %v; landed differs: (-got +want)\n%s", test.hull, ok, test.ok, diff)
+		}
+		if got, want := s.Survives(test.hull), test.ok; got != want {
+			t.Errorf("Survives(%q) = %v, want %v", test.hull, got, want)
+		}
+	}
+}
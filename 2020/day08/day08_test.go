@@ -18,64 +18,12 @@ package aocday
 import (
 	"testing"
 
+	"github.com/kylelemons/adventofcodesolutions/2020/handheld"
 	"github.com/kylelemons/adventofcodesolutions/advent"
 )
 
-type Program struct {
-	PC    int
-	Instr []Instruction
-
-	Accumulator int
-}
-
-type Instruction struct {
-	Op   string
-	Arg0 int
-}
-
-func (p *Program) Step(t *testing.T) {
-	if p.PC < 0 || p.PC > len(p.Instr) {
-		t.Fatalf("PC (%d) out of bounds (%d)", p.PC, len(p.Instr))
-	}
-	instr := p.Instr[p.PC]
-	switch instr.Op {
-	case "nop":
-	case "acc":
-		p.Accumulator += instr.Arg0
-	case "jmp":
-		p.PC += instr.Arg0 - 1 // offset the decr that will happen later
-	}
-	p.PC++
-}
-
-func (p *Program) Halted() bool {
-	return p.PC == len(p.Instr)
-}
-
-func parseInput(t *testing.T, in string) *Program {
-	input := &Program{
-		// ...
-	}
-	advent.Lines(in).Extract(t, `(\w+) ([+-]?\d+)`, func(op string, arg0 int) {
-		input.Instr = append(input.Instr, Instruction{
-			Op:   op,
-			Arg0: arg0,
-		})
-	})
-	return input
-}
-
 func part1(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	seen := make(map[int]bool)
-	for {
-		if seen[input.PC] {
-			return input.Accumulator
-		}
-		seen[input.PC] = true
-		input.Step(t)
-	}
+	return handheld.New(handheld.Parse(t, in)).Run().Accumulator
 }
 
 func TestPart1(t *testing.T) {
@@ -106,36 +54,13 @@ acc +6`, 5},
 }
 
 func part2(t *testing.T, in string) (ret int) {
-	try := func(input *Program) (acc int, halt bool) {
-		seen := make(map[int]bool)
-		for {
-			if input.Halted() {
-				return input.Accumulator, true
-			}
-			if seen[input.PC] {
-				return input.Accumulator, false
-			}
-			seen[input.PC] = true
-			input.Step(t)
-		}
-	}
-	input := parseInput(t, in)
-	for i := range input.Instr {
-		prog := parseInput(t, in)
-		switch prog.Instr[i].Op {
-		case "nop":
-			prog.Instr[i].Op = "jmp"
-		case "jmp":
-			prog.Instr[i].Op = "nop"
-		default:
-			continue
-		}
-		acc, halt := try(prog)
-		if halt {
-			return acc
-		}
+	prog := handheld.Parse(t, in)
+	patch, ok := handheld.FindPatch(prog, handheld.SwapJmpNop)
+	if !ok {
+		t.Fatalf("No patch makes the program terminate")
 	}
-	return -1
+	t.Logf("Patching %d: %v -> %v", patch.PC, prog[patch.PC], patch.Instruction)
+	return handheld.New(patch.Apply(prog)).Run().Accumulator
 }
 
 func TestPart2(t *testing.T) {
@@ -123,10 +123,8 @@ func part2(t *testing.T, in string) (ret int) {
 	sort.Ints(input.joltages)
 	input.joltages = append(input.joltages, input.joltages[len(input.joltages)-1]+3) // device
 
-	var arrange func(int) int
-
-	// Naive recursive implementation
-	arrange = func(start int) (count int) {
+	// Memoize! (poof, dynamic programming)
+	arrange := advent.Memo(func(arrange func(int) int, start int) (count int) {
 		// Base case: we're at the end!
 		if start == len(input.joltages)-1 {
 			return 1
@@ -143,19 +141,7 @@ func part2(t *testing.T, in string) (ret int) {
 			count += arrange(start + 1 + skip)
 		}
 		return
-	}
-
-	// Memoize! (poof, dynamic programming)
-	memo := make(map[int]int) // memo[start] = count
-	real := arrange
-	arrange = func(start int) int {
-		if count, ok := memo[start]; ok {
-			return count
-		}
-		count := real(start)
-		memo[start] = count
-		return count
-	}
+	})
 
 	return arrange(0)
 }
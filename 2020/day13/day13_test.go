@@ -89,31 +89,17 @@ func TestPart1(t *testing.T) {
 func part2(t *testing.T, in string) (ret int) {
 	input := parseInput(t, in)
 
-	tt := 0
-
-	for {
-		// log.Printf("t=%d", tt)
-		seq := 0
-		d := 1
-		for _, ido := range input.idOffsets {
-			if (tt+ido.offset)%ido.id == 0 {
-				seq++
-				d *= ido.id
-				continue
-			}
-			break
-		}
-		// if seq > 1 {
-		// 	log.Printf("At t=%d, %d in sequence (delta: %d)", tt, seq, d)
-		// 	for _, ido := range input.idOffsets[:seq] {
-		// 		log.Printf(" ... t=%d %d (%d cycles)", tt+ido.offset, ido.id, (tt+ido.offset)/ido.id)
-		// 	}
-		// }
-		if seq == len(input.idOffsets) {
-			return tt
-		}
-		tt += d
+	// Bus i departs at tt+offset, so tt = -offset (mod id).
+	var residues, moduli []int
+	for _, ido := range input.idOffsets {
+		residues = append(residues, -ido.offset)
+		moduli = append(moduli, ido.id)
+	}
+	tt, _, ok := advent.CRT(residues, moduli)
+	if !ok {
+		t.Fatalf("no solution for %v", input.idOffsets)
 	}
+	return tt
 }
 
 func TestPart2(t *testing.T) {
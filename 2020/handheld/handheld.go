@@ -0,0 +1,157 @@
+// Copyright 2020 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package handheld implements the 2020 Advent of Code handheld game console.
+package handheld
+
+import (
+	"fmt"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+// An Op is a handheld console operation.
+type Op uint8
+
+// The handheld console operations.
+const (
+	Nop Op = iota // does nothing
+	Acc           // adds the argument to the accumulator
+	Jmp           // jumps relative to itself by the argument
+)
+
+var opNames = [...]string{Nop: "nop", Acc: "acc", Jmp: "jmp"}
+
+// String returns the name of the operation, like "acc".
+func (op Op) String() string {
+	if int(op) >= len(opNames) {
+		return fmt.Sprintf("Op(%d)", uint8(op))
+	}
+	return opNames[op]
+}
+
+// An Instruction is an operation and its argument.
+type Instruction struct {
+	Op  Op
+	Arg int
+}
+
+// String returns the instruction in its source form, like "jmp -3".
+func (i Instruction) String() string {
+	return fmt.Sprintf("%v %+d", i.Op, i.Arg)
+}
+
+// A Program is a list of instructions.
+type Program []Instruction
+
+// Parse parses a program in its source form, one instruction per line.
+func Parse(t advent.OptionalT, source string) Program {
+	var prog Program
+	advent.Lines(source).Dispatch(t,
+		advent.On(`^nop ([+-]\d+)$`, func(arg int) { prog = append(prog, Instruction{Nop, arg}) }),
+		advent.On(`^acc ([+-]\d+)$`, func(arg int) { prog = append(prog, Instruction{Acc, arg}) }),
+		advent.On(`^jmp ([+-]\d+)$`, func(arg int) { prog = append(prog, Instruction{Jmp, arg}) }),
+	)
+	return prog
+}
+
+// Next returns the PC of the instruction executed after the one at pc.
+func (p Program) Next(pc int) int {
+	return p[pc].next(pc)
+}
+
+// next returns the PC of the instruction executed after this one, if it is at
+// the given pc.
+func (i Instruction) next(pc int) int {
+	if i.Op == Jmp {
+		return pc + i.Arg
+	}
+	return pc + 1
+}
+
+// A Console executes a Program.
+type Console struct {
+	Program     Program
+	PC          int // program counter
+	Accumulator int
+}
+
+// New returns a console which will run p from the beginning.
+func New(p Program) *Console {
+	return &Console{Program: p}
+}
+
+// Terminated returns whether the program has terminated normally, by trying
+// to execute the instruction immediately after the last one.
+func (c *Console) Terminated() bool {
+	return c.PC == len(c.Program)
+}
+
+// Running returns whether the program counter is on an instruction.
+func (c *Console) Running() bool {
+	return c.PC >= 0 && c.PC < len(c.Program)
+}
+
+// Step executes a single instruction and returns whether the console is
+// still running.
+func (c *Console) Step() bool {
+	if !c.Running() {
+		return false
+	}
+	instr := c.Program[c.PC]
+	if instr.Op == Acc {
+		c.Accumulator += instr.Arg
+	}
+	c.PC = c.Program.Next(c.PC)
+	return c.Running()
+}
+
+// A Result describes the end of a run.
+type Result struct {
+	Terminated  bool // true if the program terminated normally
+	Accumulator int  // the accumulator when the run ended
+
+	// Trace holds the PC of each instruction executed, in order.
+	Trace []int
+
+	// Loop holds the PCs of the instructions which repeat forever if the
+	// program does not terminate, starting with the first repeated one.
+	// Loop is empty if the program terminated or jumped out of bounds.
+	Loop []int
+}
+
+// Run executes the program until it stops running or is about to execute an
+// instruction for the second time, which would mean that it loops forever.
+//
+// A Console's only control flow state is its PC, so stopping before any
+// instruction is executed twice leaves the accumulator as it was at the end
+// of the first iteration through the loop.
+func (c *Console) Run() Result {
+	var (
+		res  Result
+		seen = make(map[int]int) // seen[pc] = index in trace
+	)
+	for c.Running() {
+		if start, ok := seen[c.PC]; ok {
+			res.Loop = res.Trace[start:]
+			break
+		}
+		seen[c.PC] = len(res.Trace)
+		res.Trace = append(res.Trace, c.PC)
+		c.Step()
+	}
+	res.Terminated = c.Terminated()
+	res.Accumulator = c.Accumulator
+	return res
+}
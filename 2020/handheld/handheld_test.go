@@ -0,0 +1,111 @@
+// Copyright 2020 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handheld
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const example = `nop +0
+acc +1
+jmp +4
+acc +3
+jmp -3
+acc -99
+acc +1
+jmp -4
+acc +6`
+
+func TestParse(t *testing.T) {
+	prog := Parse(t, example)
+	if got, want := len(prog), 9; got != want {
+		t.Fatalf("len(Parse(...)) = %v, want %v", got, want)
+	}
+	if got, want := prog[4], (Instruction{Jmp, -3}); got != want {
+		t.Errorf("prog[4] = %v, want %v", got, want)
+	}
+	if got, want := prog[4].String(), "jmp -3"; got != want {
+		t.Errorf("prog[4].String() = %q, want %q", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		prog string
+		want Result
+	}{
+		{
+			name: "loop",
+			prog: example,
+			want: Result{
+				Accumulator: 5,
+				Trace:       []int{0, 1, 2, 6, 7, 3, 4},
+				Loop:        []int{1, 2, 6, 7, 3, 4},
+			},
+		},
+		{
+			name: "terminates",
+			prog: "acc +2\njmp +2\nacc +100\nacc -1",
+			want: Result{
+				Terminated:  true,
+				Accumulator: 1,
+				Trace:       []int{0, 1, 3},
+			},
+		},
+		{
+			name: "out of bounds",
+			prog: "acc +7\njmp -5",
+			want: Result{
+				Accumulator: 7,
+				Trace:       []int{0, 1},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(New(Parse(t, test.prog)).Run(), test.want); diff != "" {
+				t.Errorf("Run(): (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFindPatch(t *testing.T) {
+	prog := Parse(t, example)
+	patch, ok := FindPatch(prog, SwapJmpNop)
+	if !ok {
+		t.Fatalf("FindPatch found no patch")
+	}
+	if got, want := patch, (Patch{PC: 7, Instruction: Instruction{Nop, -4}}); got != want {
+		t.Errorf("FindPatch = %+v, want %+v", got, want)
+	}
+	res := New(patch.Apply(prog)).Run()
+	if !res.Terminated || res.Accumulator != 8 {
+		t.Errorf("patched program: %+v, want termination with accumulator 8", res)
+	}
+	if got, want := prog[7], (Instruction{Jmp, -4}); got != want {
+		t.Errorf("Apply modified the original program: prog[7] = %v, want %v", got, want)
+	}
+
+	if _, ok := FindPatch(Parse(t, "jmp +0\nacc +1"), func(Instruction) []Instruction { return nil }); ok {
+		t.Errorf("FindPatch with no alternatives found a patch")
+	}
+	if _, ok := FindPatch(Parse(t, "nop +0\nacc +1"), SwapJmpNop); ok {
+		t.Errorf("FindPatch for a terminating program found a patch")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handheld
+
+// A Patch replaces a single instruction in a program.
+type Patch struct {
+	PC          int
+	Instruction Instruction
+}
+
+// Apply returns a copy of p with the patch applied.
+func (pt Patch) Apply(p Program) Program {
+	patched := append(Program(nil), p...)
+	patched[pt.PC] = pt.Instruction
+	return patched
+}
+
+// SwapJmpNop is an alternatives function for FindPatch which turns a jmp into
+// a nop or a nop into a jmp.
+func SwapJmpNop(instr Instruction) []Instruction {
+	switch instr.Op {
+	case Jmp:
+		return []Instruction{{Nop, instr.Arg}}
+	case Nop:
+		return []Instruction{{Jmp, instr.Arg}}
+	}
+	return nil
+}
+
+// FindPatch finds a single instruction to replace (with one of the options
+// returned by alternatives) which makes p terminate.  If there is more than
+// one such patch, the one closest to the start of the execution is returned.
+//
+// Rather than running every patched program, FindPatch first works backward
+// from the end of the program to find all of the instructions from which it
+// terminates.  Only instructions which the unpatched program executes can be
+// patched usefully, and a patch works if it leads to one of those
+// instructions, so the search is linear in the size of the program.
+//
+// If p already terminates, no patch is needed and FindPatch returns false.
+func FindPatch(p Program, alternatives func(Instruction) []Instruction) (Patch, bool) {
+	// The program terminates if it reaches len(p), so work backward from
+	// there to find every PC which leads to termination.
+	preds := make(map[int][]int)
+	for pc := range p {
+		next := p.Next(pc)
+		preds[next] = append(preds[next], pc)
+	}
+	terminates := map[int]bool{len(p): true}
+	for queue := []int{len(p)}; len(queue) > 0; queue = queue[1:] {
+		for _, pred := range preds[queue[0]] {
+			if !terminates[pred] {
+				terminates[pred] = true
+				queue = append(queue, pred)
+			}
+		}
+	}
+
+	// The unpatched program executes the same instructions up until the
+	// patch, so check each one that it executes.  None of these lead to
+	// termination already, so a patched instruction's path to the end never
+	// passes back through it.
+	res := New(p).Run()
+	if res.Terminated {
+		return Patch{}, false
+	}
+	for _, pc := range res.Trace {
+		for _, alt := range alternatives(p[pc]) {
+			if terminates[alt.next(pc)] {
+				return Patch{PC: pc, Instruction: alt}, true
+			}
+		}
+	}
+	return Patch{}, false
+}
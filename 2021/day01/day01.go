@@ -0,0 +1,70 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aocday is the entrypoint for this AoC solution.
+package aocday
+
+import (
+	"math"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+func init() {
+	advent.Register(2021, 1, 1, func(t advent.OptionalT, in string) any { return part1(t, in) })
+	advent.Register(2021, 1, 2, func(t advent.OptionalT, in string) any { return part2(t, in) })
+}
+
+type Input struct {
+	depths []int
+}
+
+func parseInput(t advent.OptionalT, in string) *Input {
+	input := &Input{
+		// ...
+	}
+	advent.Lines(in).Extract(t, `(.*)`, func(depth int) {
+		input.depths = append(input.depths, depth)
+	})
+	return input
+}
+
+func part1(t advent.OptionalT, in string) (ret int) {
+	input := parseInput(t, in)
+
+	prev := math.MaxInt
+	for _, d := range input.depths {
+		if d > prev {
+			ret++
+		}
+		prev = d
+	}
+
+	return
+}
+
+func part2(t advent.OptionalT, in string) (ret int) {
+	input := parseInput(t, in)
+
+	for i := range input.depths {
+		if i <= 2 {
+			continue
+		}
+		if input.depths[i] > input.depths[i-3] {
+			ret++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aocday is the entrypoint for this AoC solution.
+package aocday
+
+import (
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+func init() {
+	advent.Register(2021, 2, 1, func(t advent.OptionalT, in string) any { return part1(t, in) })
+	advent.Register(2021, 2, 2, func(t advent.OptionalT, in string) any { return part2(t, in) })
+}
+
+type Command struct {
+	What  string
+	Count int
+}
+
+type Input struct {
+	Commands []Command
+}
+
+func parseInput(t advent.OptionalT, in string) *Input {
+	input := &Input{
+		// ...
+	}
+	advent.Lines(in).Scan(t, func(what string, count int) {
+		input.Commands = append(input.Commands, Command{what, count})
+	})
+	return input
+}
+
+func part1(t advent.OptionalT, in string) (ret int) {
+	input := parseInput(t, in)
+
+	var pos, depth int
+	for _, c := range input.Commands {
+		switch c.What {
+		case "forward":
+			pos += c.Count
+		case "down":
+			depth += c.Count
+		case "up":
+			depth -= c.Count
+		}
+	}
+
+	return pos * depth
+}
+
+func part2(t advent.OptionalT, in string) (ret int) {
+	input := parseInput(t, in)
+
+	var pos, depth, aim int
+	for _, c := range input.Commands {
+		switch c.What {
+		case "forward":
+			pos += c.Count
+			depth += aim * c.Count
+		case "down":
+			aim += c.Count
+		case "up":
+			aim -= c.Count
+		}
+	}
+
+	return pos * depth
+}
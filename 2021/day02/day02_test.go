@@ -12,7 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package acoday is the entrypoint for this AoC solution.
 package aocday
 
 import (
@@ -21,43 +20,6 @@ import (
 	"github.com/kylelemons/adventofcodesolutions/advent"
 )
 
-type Command struct {
-	What  string
-	Count int
-}
-
-type Input struct {
-	Commands []Command
-}
-
-func parseInput(t *testing.T, in string) *Input {
-	input := &Input{
-		// ...
-	}
-	advent.Lines(in).Scan(t, func(what string, count int) {
-		input.Commands = append(input.Commands, Command{what, count})
-	})
-	return input
-}
-
-func part1(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	var pos, depth int
-	for _, c := range input.Commands {
-		switch c.What {
-		case "forward":
-			pos += c.Count
-		case "down":
-			depth += c.Count
-		case "up":
-			depth -= c.Count
-		}
-	}
-
-	return pos * depth
-}
-
 func TestPart1(t *testing.T) {
 	tests := []struct {
 		name string
@@ -82,25 +44,6 @@ forward 2`, 150},
 	}
 }
 
-func part2(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	var pos, depth, aim int
-	for _, c := range input.Commands {
-		switch c.What {
-		case "forward":
-			pos += c.Count
-			depth += aim * c.Count
-		case "down":
-			aim += c.Count
-		case "up":
-			aim -= c.Count
-		}
-	}
-
-	return pos * depth
-}
-
 func TestPart2(t *testing.T) {
 	tests := []struct {
 		name string
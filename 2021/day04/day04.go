@@ -0,0 +1,138 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aocday is the entrypoint for this AoC solution.
+package aocday
+
+import (
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+func init() {
+	advent.Register(2021, 4, 1, func(t advent.OptionalT, in string) any { return part1(t, in) })
+	advent.Register(2021, 4, 2, func(t advent.OptionalT, in string) any { return part2(t, in) })
+}
+
+type Input struct {
+	Draws     []int
+	Boards    [][][]int
+	Locations map[int][]loc
+}
+
+type loc struct {
+	board int
+	id    int // 0-4 row, 5+ col
+}
+
+func parseInput(t advent.OptionalT, in string) *Input {
+	input := &Input{
+		// ...
+	}
+	records := advent.Records(in).All(t)
+
+	advent.Split(records[0], ',').Scan(t, func(i int) {
+		input.Draws = append(input.Draws, i)
+	})
+	for _, rec := range records[1:] {
+		var board [][]int
+		advent.Lines(rec).Scan(t, func(a, b, c, d, e int) {
+			board = append(board, []int{a, b, c, d, e})
+		})
+		input.Boards = append(input.Boards, board)
+	}
+
+	nums := make(map[int][]loc)
+	for i, b := range input.Boards {
+		for r := range b {
+			for c := range b[r] {
+				num := b[r][c]
+				nums[num] = append(nums[num], loc{
+					board: i,
+					id:    r,
+				})
+				nums[num] = append(nums[num], loc{
+					board: i,
+					id:    5 + c,
+				})
+			}
+		}
+	}
+	input.Locations = nums
+	return input
+}
+
+func part1(t advent.OptionalT, in string) (ret int) {
+	input := parseInput(t, in)
+
+	counts := map[loc]int{}
+	marked := map[int]bool{}
+	for _, draw := range input.Draws {
+		marked[draw] = true
+		for _, l := range input.Locations[draw] {
+			counts[l]++
+			if counts[l] == 5 {
+				var unmarked int
+				for _, row := range input.Boards[l.board] {
+					for _, num := range row {
+						if marked[num] {
+							continue
+						}
+						unmarked += num
+					}
+				}
+				return unmarked * draw
+			}
+		}
+	}
+
+	return -1
+}
+
+func part2(t advent.OptionalT, in string) (ret int) {
+	input := parseInput(t, in)
+
+	counts := map[loc]int{}
+	marked := map[int]bool{}
+	alive := map[int]bool{}
+	for i := range input.Boards {
+		alive[i] = true
+	}
+	for _, draw := range input.Draws {
+		marked[draw] = true
+		for _, l := range input.Locations[draw] {
+			if !alive[l.board] {
+				continue
+			}
+			counts[l]++
+			if counts[l] == 5 {
+				delete(alive, l.board)
+				if len(alive) > 0 {
+					continue
+				}
+				var unmarked int
+				for _, row := range input.Boards[l.board] {
+					for _, num := range row {
+						if marked[num] {
+							continue
+						}
+						unmarked += num
+					}
+				}
+				return unmarked * draw
+			}
+		}
+	}
+
+	return -1
+}
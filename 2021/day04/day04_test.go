@@ -12,7 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package acoday is the entrypoint for this AoC solution.
 package aocday
 
 import (
@@ -21,81 +20,6 @@ import (
 	"github.com/kylelemons/adventofcodesolutions/advent"
 )
 
-type Input struct {
-	Draws     []int
-	Boards    [][][]int
-	Locations map[int][]loc
-}
-
-type loc struct {
-	board int
-	id    int // 0-4 row, 5+ col
-}
-
-func parseInput(t *testing.T, in string) *Input {
-	input := &Input{
-		// ...
-	}
-	records := advent.Records(in).All(t)
-
-	advent.Split(records[0], ',').Scan(t, func(i int) {
-		input.Draws = append(input.Draws, i)
-	})
-	for _, rec := range records[1:] {
-		var board [][]int
-		advent.Lines(rec).Scan(t, func(a, b, c, d, e int) {
-			board = append(board, []int{a, b, c, d, e})
-		})
-		input.Boards = append(input.Boards, board)
-	}
-
-	nums := make(map[int][]loc)
-	for i, b := range input.Boards {
-		for r := range b {
-			for c := range b[r] {
-				num := b[r][c]
-				nums[num] = append(nums[num], loc{
-					board: i,
-					id:    r,
-				})
-				nums[num] = append(nums[num], loc{
-					board: i,
-					id:    5 + c,
-				})
-			}
-		}
-	}
-	input.Locations = nums
-	return input
-}
-
-func part1(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	counts := map[loc]int{}
-	marked := map[int]bool{}
-	for _, draw := range input.Draws {
-		marked[draw] = true
-		for _, l := range input.Locations[draw] {
-			counts[l]++
-			if counts[l] == 5 {
-				var unmarked int
-				for _, row := range input.Boards[l.board] {
-					for _, num := range row {
-						if marked[num] {
-							continue
-						}
-						unmarked += num
-					}
-				}
-				return unmarked * draw
-			}
-		}
-	}
-
-	return -1
-}
-
 func TestPart1(t *testing.T) {
 	tests := []struct {
 		name string
@@ -133,44 +57,6 @@ func TestPart1(t *testing.T) {
 	}
 }
 
-func part2(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	counts := map[loc]int{}
-	marked := map[int]bool{}
-	alive := map[int]bool{}
-	for i := range input.Boards {
-		alive[i] = true
-	}
-	for _, draw := range input.Draws {
-		marked[draw] = true
-		for _, l := range input.Locations[draw] {
-			if !alive[l.board] {
-				continue
-			}
-			counts[l]++
-			if counts[l] == 5 {
-				delete(alive, l.board)
-				if len(alive) > 0 {
-					continue
-				}
-				var unmarked int
-				for _, row := range input.Boards[l.board] {
-					for _, num := range row {
-						if marked[num] {
-							continue
-						}
-						unmarked += num
-					}
-				}
-				return unmarked * draw
-			}
-		}
-	}
-
-	return -1
-}
-
 func TestPart2(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,90 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aocday is the entrypoint for this AoC solution.
+package aocday
+
+import (
+	"math/big"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+func init() {
+	advent.Register(2021, 6, 1, func(t advent.OptionalT, in string) any { return part1(t, in) })
+	advent.Register(2021, 6, 2, func(t advent.OptionalT, in string) any { return part2(t, in) })
+}
+
+type Input struct {
+	FishAtCounter [9]int
+}
+
+func parseInput(t advent.OptionalT, in string) *Input {
+	input := &Input{
+		// ...
+	}
+	advent.Split(in, ',').Scan(t, func(counter int) {
+		input.FishAtCounter[counter]++
+	})
+	return input
+}
+
+func part1(t advent.OptionalT, in string) (ret int) {
+	input := parseInput(t, in)
+
+	const SimulateDays = 80
+
+	for i := 0; i < SimulateDays; i++ {
+		var next [9]int
+		for counter, fish := range input.FishAtCounter {
+			if counter == 0 {
+				next[6] += fish // time to spawn a new fish
+				next[8] += fish // new fish that spawned
+				continue
+			}
+			next[counter-1] += fish
+		}
+		input.FishAtCounter = next
+	}
+
+	for _, fish := range input.FishAtCounter {
+		ret += fish
+	}
+	return
+}
+
+func part2(t advent.OptionalT, in string) (ret *big.Int) {
+	input := parseInput(t, in)
+
+	const SimulateDays = 256
+
+	for i := 0; i < SimulateDays; i++ {
+		var next [9]int
+		for counter, fish := range input.FishAtCounter {
+			if counter == 0 {
+				next[6] += fish // time to spawn a new fish
+				next[8] += fish // new fish that spawned
+				continue
+			}
+			next[counter-1] += fish
+		}
+		input.FishAtCounter = next
+	}
+
+	total := new(big.Int)
+	for _, fish := range input.FishAtCounter {
+		total.Add(total, big.NewInt(int64(fish)))
+	}
+	return total
+}
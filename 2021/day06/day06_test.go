@@ -12,56 +12,15 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package acoday is the entrypoint for this AoC solution.
 package aocday
 
 import (
-	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/kylelemons/adventofcodesolutions/advent"
 )
 
-type Input struct {
-	FishAtCounter [9]int
-}
-
-func parseInput(t *testing.T, in string) *Input {
-	input := &Input{
-		// ...
-	}
-	advent.Split(in, ',').Scan(t, func(counter int) {
-		input.FishAtCounter[counter]++
-	})
-	return input
-}
-
-func part1(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	const SimulateDays = 80
-
-	for i := 0; i < SimulateDays; i++ {
-		fmt.Println(input.FishAtCounter)
-		var next [9]int
-		for counter, fish := range input.FishAtCounter {
-			if counter == 0 {
-				next[6] += fish // time to spawn a new fish
-				next[8] += fish // new fish that spawned
-				continue
-			}
-			next[counter-1] += fish
-		}
-		input.FishAtCounter = next
-	}
-
-	for _, fish := range input.FishAtCounter {
-		ret += fish
-	}
-	return
-}
-
 func TestPart1(t *testing.T) {
 	tests := []struct {
 		name string
@@ -81,32 +40,6 @@ func TestPart1(t *testing.T) {
 	}
 }
 
-func part2(t *testing.T, in string) (ret *big.Int) {
-	input := parseInput(t, in)
-
-	const SimulateDays = 256
-
-	for i := 0; i < SimulateDays; i++ {
-		fmt.Println(input.FishAtCounter)
-		var next [9]int
-		for counter, fish := range input.FishAtCounter {
-			if counter == 0 {
-				next[6] += fish // time to spawn a new fish
-				next[8] += fish // new fish that spawned
-				continue
-			}
-			next[counter-1] += fish
-		}
-		input.FishAtCounter = next
-	}
-
-	total := new(big.Int)
-	for _, fish := range input.FishAtCounter {
-		total.Add(total, big.NewInt(int64(fish)))
-	}
-	return total
-}
-
 func TestPart2(t *testing.T) {
 	tests := []struct {
 		name string
@@ -12,52 +12,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package acoday is the entrypoint for this AoC solution.
 package aocday
 
 import (
-	"math"
-	"sort"
 	"testing"
 
 	"github.com/kylelemons/adventofcodesolutions/advent"
 )
 
-type Input struct {
-	Positions []int
-}
-
-func parseInput(t *testing.T, in string) *Input {
-	input := &Input{
-		// ...
-	}
-	advent.Split(in, ',').Scan(t, func(v int) {
-		input.Positions = append(input.Positions, v)
-	})
-	sort.Ints(input.Positions)
-	return input
-}
-
-func part1(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	ret = math.MaxInt
-	for loc := input.Positions[0]; loc <= input.Positions[len(input.Positions)-1]; loc++ {
-		var fuel int
-		for _, pos := range input.Positions {
-			delta := pos - loc
-			if delta < 0 {
-				delta = -delta
-			}
-			fuel += delta
-		}
-		if fuel < ret {
-			ret = fuel
-		}
-	}
-	return
-}
-
 func TestPart1(t *testing.T) {
 	tests := []struct {
 		name string
@@ -77,44 +39,6 @@ func TestPart1(t *testing.T) {
 	}
 }
 
-func part2(t *testing.T, in string) (ret int) {
-	input := parseInput(t, in)
-
-	var fuelFor func(delta int) int
-	fuelFor = func(delta int) int {
-		if delta <= 0 {
-			return 0
-		}
-		return delta + fuelFor(delta-1)
-	}
-	cache := make(map[int]int)
-	orig := fuelFor
-	fuelFor = func(delta int) int {
-		if ans, ok := cache[delta]; ok {
-			return ans
-		}
-		ans := orig(delta)
-		cache[delta] = ans
-		return ans
-	}
-
-	ret = math.MaxInt
-	for loc := input.Positions[0]; loc <= input.Positions[len(input.Positions)-1]; loc++ {
-		var fuel int
-		for _, pos := range input.Positions {
-			delta := pos - loc
-			if delta < 0 {
-				delta = -delta
-			}
-			fuel += fuelFor(delta)
-		}
-		if fuel < ret {
-			ret = fuel
-		}
-	}
-	return
-}
-
 func TestPart2(t *testing.T) {
 	tests := []struct {
 		name string
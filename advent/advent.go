@@ -21,10 +21,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
 	"unicode/utf8"
+
+	"github.com/kylelemons/adventofcodesolutions/advent/input"
 )
 
 // Scanner is a helper for doing simple linewise scanning.
@@ -55,6 +59,14 @@ func maybeT(t OptionalT) OptionalT {
 	return defaultT{}
 }
 
+// MaybeT returns t, or the default implementation if t is nil.
+//
+// Packages outside of advent that accept an OptionalT can use this to honor
+// the same nil convention.
+func MaybeT(t OptionalT) OptionalT {
+	return maybeT(t)
+}
+
 // Scan scans the string into the given pointers using fmt.Sscan.
 func (s Scanner) Scan(t OptionalT, ptrs ...interface{}) {
 	t = maybeT(t)
@@ -93,8 +105,15 @@ func (s Scanner) CanExtract(t OptionalT, re string, ptrs ...interface{}) bool {
 	if matches == nil {
 		return false
 	}
+	storeGroups(t, matches[1:], ptrs)
+	return true
+}
+
+// storeGroups stores the matched capture groups into the corresponding pointers.
+func storeGroups(t OptionalT, groups []string, ptrs []interface{}) {
+	t.Helper()
 	for i, ptr := range ptrs {
-		val := matches[i+1]
+		val := groups[i]
 		if got, want := reflect.TypeOf(ptr).Kind(), reflect.Ptr; got != want {
 			t.Fatalf("can't scan into group %d: got %v, want %v", i+1, got, want)
 		}
@@ -109,48 +128,231 @@ func (s Scanner) CanExtract(t OptionalT, re string, ptrs ...interface{}) bool {
 			}
 		}
 	}
-	return true
 }
 
 // ReadFile reads the named file and returns it as a string.
+//
+// If the file is named input.txt but does not exist, and the directory
+// containing it is a solution directory (e.g. 2019/day18), the input is instead
+// loaded from the local input cache, downloading it if necessary.  See package
+// input for how to configure the cache and download credentials.
 func ReadFile(t OptionalT, filename string) string {
 	t = maybeT(t)
 	t.Helper()
 	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) && filepath.Base(filename) == "input.txt" {
+		if cached, cacheErr := readCachedInput(filepath.Dir(filename)); cacheErr == nil {
+			data, err = []byte(cached), nil
+		} else {
+			err = fmt.Errorf("%s (and %s)", err, cacheErr)
+		}
+	}
 	if err != nil {
 		t.Fatalf("failed to read %q: %s", filename, err)
 	}
 	return strings.TrimRight(string(data), "\n")
 }
 
+// readCachedInput returns the input for the solution in dir from the cache.
+func readCachedInput(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	year, day, ok := input.DayFromPath(dir)
+	if !ok {
+		return "", fmt.Errorf("cannot determine puzzle day from %q", dir)
+	}
+	return input.FromEnv().Get(year, day)
+}
+
 // Delimited is a helper for processing delimited inputs.
+//
+// Delimited helpers created by this package track the position of each token
+// in the original input, which is available via Line and Offset and which is
+// included in the errors reported by Scan and Extract.
 type Delimited struct {
 	Scanner *bufio.Scanner
+
+	parent *Delimited // set for helpers returned by Take
+	limit  int        // remaining tokens for helpers returned by Take
+
+	consumed Position // position of the first byte not yet consumed
+	next     Position // position of the token most recently split
+	current  Position // position of the token most recently returned
+	previous Position // position of the token returned before current
+	last     string   // token most recently returned
+	lastPos  Position // position of last, which Unread may not be current
+	unread   bool     // true if the last token should be returned again
+	returned bool     // true if Next has returned a token which can be unread
+}
+
+// A Position identifies a location within the input to a Delimited helper.
+type Position struct {
+	Line   int // 1-based line number
+	Offset int // 0-based byte offset
+}
+
+// String returns the position in "line N" form.
+func (p Position) String() string {
+	return fmt.Sprintf("line %d", p.Line)
+}
+
+// Next returns the next token from the input, including empty tokens.
+//
+// The returned bool is false when the input is exhausted or an error has
+// occurred, in which case the error can be retrieved with Err.
+func (d *Delimited) Next() (token Scanner, ok bool) {
+	if d.parent != nil {
+		if d.limit <= 0 {
+			return "", false
+		}
+		if token, ok = d.parent.Next(); ok {
+			d.limit--
+			d.unread, d.returned = false, true
+		}
+		return token, ok
+	}
+
+	if d.unread {
+		d.unread, d.returned = false, true
+		d.previous, d.current = d.current, d.lastPos
+		return Scanner(d.last), true
+	}
+	if !d.Scanner.Scan() {
+		return "", false
+	}
+	d.last, d.lastPos = d.Scanner.Text(), d.next
+	d.previous, d.current = d.current, d.lastPos
+	d.returned = true
+	return Scanner(d.last), true
+}
+
+// Unread causes the token most recently returned by Next to be returned again.
+// Until then, Position reports the token returned before it.
+//
+// Only one token can be unread at a time; calling Unread twice without an
+// intervening Next will panic, as will calling it before Next has returned a
+// token.  A helper returned by Take can only unread its own tokens.
+func (d *Delimited) Unread() {
+	if d.unread {
+		panic("Unread called twice without an intervening Next")
+	}
+	if !d.returned {
+		panic("Unread called before Next")
+	}
+	d.unread, d.returned = true, false
+	if d.parent != nil {
+		d.parent.Unread()
+		d.limit++
+		return
+	}
+	d.current = d.previous
 }
 
+// Peek returns the next token without consuming it.  Position is unaffected.
+func (d *Delimited) Peek() (token Scanner, ok bool) {
+	if token, ok = d.Next(); ok {
+		d.Unread()
+	}
+	return token, ok
+}
+
+// Take returns a Delimited helper which returns at most the next n tokens
+// from d.  Tokens consumed from the returned helper are also consumed from d,
+// which makes it possible to process inputs in several different formats:
+//
+//	in := advent.Lines(input)
+//	in.Take(1).Scan(t, func(start int) { ... })
+//	in.Extract(t, `(\w+) -> (\w+)`, func(from, to string) { ... })
+func (d *Delimited) Take(n int) *Delimited {
+	return &Delimited{
+		parent: d,
+		limit:  n,
+	}
+}
+
+// Err returns the first non-EOF error encountered while scanning.
+func (d *Delimited) Err() error {
+	if d.parent != nil {
+		return d.parent.Err()
+	}
+	return d.Scanner.Err()
+}
+
+// Position returns the position at which the token most recently returned
+// by Next begins.
+func (d *Delimited) Position() Position {
+	if d.parent != nil {
+		return d.parent.Position()
+	}
+	return d.current
+}
+
+// Line returns the line number on which the most recent token begins.
+func (d *Delimited) Line() int { return d.Position().Line }
+
+// Offset returns the byte offset at which the most recent token begins.
+func (d *Delimited) Offset() int { return d.Position().Offset }
+
 // All returns all of the delimited values, one per string.
 func (d *Delimited) All(t OptionalT) (values []string) {
-	for d.Scanner.Scan() {
-		values = append(values, d.Scanner.Text())
+	t = maybeT(t)
+	t.Helper()
+	for {
+		token, ok := d.Next()
+		if !ok {
+			break
+		}
+		values = append(values, string(token))
 	}
-	if err := d.Scanner.Err(); err != nil {
+	if err := d.Err(); err != nil {
 		t.Fatalf("Failed to scan after %d values: %s", len(values), err)
 	}
 	return
 }
 
 // Lines returns a Delimited helper for line-delimited inputs.
-func Lines(input string) *Delimited {
-	return &Delimited{
-		Scanner: bufio.NewScanner(strings.NewReader(input)),
+func Lines(input string) *Delimited { return withSplitter(input, bufio.ScanLines) }
+
+func withSplitter(input string, splitter bufio.SplitFunc) *Delimited {
+	d := &Delimited{
+		consumed: Position{Line: 1},
 	}
+	d.Scanner = bufio.NewScanner(strings.NewReader(input))
+	d.Scanner.Split(d.tracking(splitter))
+	return d
 }
 
-func withSplitter(input string, splitter bufio.SplitFunc) *Delimited {
-	s := bufio.NewScanner(strings.NewReader(input))
-	s.Split(splitter)
-	return &Delimited{
-		Scanner: s,
+// tracking wraps the splitter so that the position of each token is recorded.
+func (d *Delimited) tracking(splitter bufio.SplitFunc) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		advance, token, err = splitter(data, atEOF)
+		if advance <= 0 && token == nil {
+			return advance, token, err
+		}
+		if advance > len(data) {
+			// Let bufio.Scanner report this error.
+			return advance, token, err
+		}
+		consumed := data[:advance]
+		if token != nil {
+			// Splitters skip leading delimiters, which by definition cannot be the
+			// beginning of the token, so the first match is the token start.
+			start := 0
+			if len(token) > 0 {
+				if i := bytes.Index(consumed, token); i > 0 {
+					start = i
+				}
+			}
+			d.next = Position{
+				Line:   d.consumed.Line + bytes.Count(consumed[:start], []byte{'\n'}),
+				Offset: d.consumed.Offset + start,
+			}
+		}
+		d.consumed.Line += bytes.Count(consumed, []byte{'\n'})
+		d.consumed.Offset += advance
+		return advance, token, err
 	}
 }
 
@@ -206,13 +408,19 @@ func Split(input string, delim rune) *Delimited {
 
 // Each calls f for each successive non-empty token with the token index and
 // a scanner to use in parsing the token.
+//
+// The index counts only non-empty tokens; use Line or Position to determine
+// where in the input the current token can be found.
 func (d *Delimited) Each(each func(i int, token Scanner)) {
-	for i := 0; d.Scanner.Scan(); {
-		token := d.Scanner.Text()
+	for i := 0; ; {
+		token, ok := d.Next()
+		if !ok {
+			return
+		}
 		if token == "" {
 			continue
 		}
-		each(i, Scanner(token))
+		each(i, token)
 		i++
 	}
 }
@@ -228,7 +436,7 @@ func (d *Delimited) Scan(t OptionalT, each interface{}) {
 	fval := reflect.ValueOf(each)
 	pointers, values := inputsFor(fval)
 	d.Each(func(_ int, token Scanner) {
-		token.Scan(t, pointers...)
+		token.Scan(d.positionT(t), pointers...)
 		fval.Call(values)
 	})
 }
@@ -244,11 +452,32 @@ func (d *Delimited) Extract(t OptionalT, re string, each interface{}) {
 	fval := reflect.ValueOf(each)
 	pointers, values := inputsFor(fval)
 	d.Each(func(_ int, token Scanner) {
-		token.Extract(t, re, pointers...)
+		token.Extract(d.positionT(t), re, pointers...)
 		fval.Call(values)
 	})
 }
 
+// positionT returns an OptionalT which prefixes failures with the position of
+// the current token.
+func (d *Delimited) positionT(t OptionalT) OptionalT {
+	pos := d.Position()
+	if pos.Line == 0 {
+		// Position tracking is not available.
+		return t
+	}
+	return positionT{t, pos}
+}
+
+type positionT struct {
+	OptionalT
+	pos Position
+}
+
+func (t positionT) Fatalf(format string, args ...interface{}) {
+	t.OptionalT.Helper()
+	t.OptionalT.Fatalf("%s: "+format, append([]interface{}{t.pos}, args...)...)
+}
+
 // inputsFor returns two slices based on the given reflective function value:
 //  - a []interface{} for passing to fmt.Scan* corresponding 1:1 to params
 //  - a []reflect.Value for calling the fval function with the internal params
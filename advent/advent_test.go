@@ -16,6 +16,7 @@
 package advent
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -51,3 +52,220 @@ func TestRecords(t *testing.T) {
 		}
 	}
 }
+
+func TestDelimitedPosition(t *testing.T) {
+	type token struct {
+		Text string
+		Pos  Position
+	}
+	tests := []struct {
+		name string
+		d    *Delimited
+		want []token
+	}{
+		{
+			name: "lines",
+			d:    Lines("a\n\nbc\nd"),
+			want: []token{
+				{"a", Position{1, 0}},
+				{"", Position{2, 2}},
+				{"bc", Position{3, 3}},
+				{"d", Position{4, 6}},
+			},
+		},
+		{
+			name: "words",
+			d:    Words("  a b\n\n  cd "),
+			want: []token{
+				{"a", Position{1, 2}},
+				{"b", Position{1, 4}},
+				{"cd", Position{3, 9}},
+			},
+		},
+		{
+			name: "records",
+			d:    Records("\na\nb\n\n\nc\n\nd\n"),
+			want: []token{
+				{"a\nb", Position{2, 1}},
+				{"c", Position{6, 7}},
+				{"d", Position{8, 10}},
+			},
+		},
+		{
+			name: "split",
+			d:    Split("1,,23,\n4", ','),
+			want: []token{
+				{"1", Position{1, 0}},
+				{"", Position{1, 2}},
+				{"23", Position{1, 3}},
+				{"\n4", Position{1, 6}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []token
+			for {
+				tok, ok := test.d.Next()
+				if !ok {
+					break
+				}
+				got = append(got, token{string(tok), test.d.Position()})
+			}
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("tokens differ: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelimitedPeekUnreadTake(t *testing.T) {
+	in := Lines("3\nR 1\nL 2\nU 3\n\nx=1\nx=2")
+
+	if tok, ok := in.Peek(); !ok || tok != "3" {
+		t.Fatalf("Peek() = %q, %v, want %q, true", tok, ok, "3")
+	}
+
+	var n int
+	in.Take(1).Scan(t, func(v int) { n = v })
+	if got, want := n, 3; got != want {
+		t.Fatalf("header = %v, want %v", got, want)
+	}
+
+	var moves []string
+	in.Take(n).Extract(t, `([RLUD]) (\d+)`, func(dir string, dist int) {
+		moves = append(moves, fmt.Sprint(dir, dist))
+	})
+	if diff := cmp.Diff(moves, []string{"R1", "L2", "U3"}); diff != "" {
+		t.Errorf("moves differ: (-got +want)\n%s", diff)
+	}
+
+	in.Next() // blank line
+	if got, want := in.Line(), 5; got != want {
+		t.Errorf("Line() = %v, want %v", got, want)
+	}
+	if tok, _ := in.Peek(); tok != "x=1" {
+		t.Errorf("Peek() = %q, want %q", tok, "x=1")
+	}
+	if got, want := in.Position(), (Position{Line: 5, Offset: 14}); got != want {
+		t.Errorf("Position() after Peek = %v, want %v", got, want)
+	}
+	tok, _ := in.Next()
+	in.Unread()
+	if again, _ := in.Next(); again != tok {
+		t.Errorf("Next() after Unread = %q, want %q", again, tok)
+	}
+	if got, want := in.Line(), 6; got != want {
+		t.Errorf("Line() = %v, want %v", got, want)
+	}
+
+	if diff := cmp.Diff(in.All(t), []string{"x=2"}); diff != "" {
+		t.Errorf("remaining tokens differ: (-got +want)\n%s", diff)
+	}
+}
+
+func TestDelimitedUnreadMisuse(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(in *Delimited)
+		want  string // panic message
+	}{
+		{
+			name:  "before Next",
+			setup: func(in *Delimited) { in.Unread() },
+			want:  "Unread called before Next",
+		},
+		{
+			name:  "twice",
+			setup: func(in *Delimited) { in.Next(); in.Unread(); in.Unread() },
+			want:  "Unread called twice without an intervening Next",
+		},
+		{
+			name:  "Take before Next",
+			setup: func(in *Delimited) { in.Next(); in.Take(1).Unread() },
+			want:  "Unread called before Next",
+		},
+		{
+			name: "Take twice",
+			setup: func(in *Delimited) {
+				take := in.Take(1)
+				take.Next()
+				take.Unread()
+				take.Unread()
+			},
+			want: "Unread called twice without an intervening Next",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in := Lines("a\nb")
+			func() {
+				defer func() {
+					if got, want := fmt.Sprint(recover()), test.want; got != want {
+						t.Errorf("panic = %q, want %q", got, want)
+					}
+				}()
+				test.setup(in)
+			}()
+		})
+	}
+}
+
+func TestDelimitedTakeUnread(t *testing.T) {
+	in := Lines("a\nb\nc")
+	in.Next()
+
+	take := in.Take(1)
+	if tok, ok := take.Peek(); !ok || tok != "b" {
+		t.Fatalf("Take(1).Peek() = %q, %v, want %q, true", tok, ok, "b")
+	}
+	if diff := cmp.Diff(take.All(t), []string{"b"}); diff != "" {
+		t.Errorf("Take(1) tokens differ: (-got +want)\n%s", diff)
+	}
+	if diff := cmp.Diff(in.All(t), []string{"c"}); diff != "" {
+		t.Errorf("remaining tokens differ: (-got +want)\n%s", diff)
+	}
+}
+
+type fatalT struct {
+	msg string
+}
+
+type fatal struct{}
+
+func (t *fatalT) Helper() {}
+
+func (t *fatalT) Fatalf(format string, args ...interface{}) {
+	t.msg = fmt.Sprintf(format, args...)
+	panic(fatal{})
+}
+
+func (t *fatalT) catch(f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(fatal); !ok {
+				panic(r)
+			}
+		}
+	}()
+	f()
+}
+
+func TestDelimitedErrors(t *testing.T) {
+	ft := new(fatalT)
+	ft.catch(func() {
+		Lines("a1\n\nb2\nc\nd4").Extract(ft, `(\w)(\d)`, func(string, int) {})
+	})
+	if got, want := ft.msg, `line 4: Input "c" does not match /(\w)(\d)/`; got != want {
+		t.Errorf("Extract failure = %q, want %q", got, want)
+	}
+
+	ft = new(fatalT)
+	ft.catch(func() {
+		Records("1 2\n\n3 x").Scan(ft, func(a, b int) {})
+	})
+	if got, want := ft.msg, "line 3: Sscan: expected integer"; got != want {
+		t.Errorf("Scan failure = %q, want %q", got, want)
+	}
+}
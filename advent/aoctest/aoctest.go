@@ -0,0 +1,229 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aoctest is a table-driven test harness for Advent of Code solutions
+// which keeps the inputs and expected answers in files next to the solution.
+//
+// The harness runs each part on every example*.txt file and on input.txt, and
+// compares the results against the golden answers recorded in answers.json:
+//
+//	{
+//	  "part1": {"example1.txt": 7, "input.txt": 1665},
+//	  "part2": {"example1.txt": 5, "input.txt": 1702}
+//	}
+//
+// Inputs without a recorded answer for a part are skipped.  Running the tests
+// with -aoctest.update records the current results as the new golden answers.
+package aoctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+var update = flag.Bool("aoctest.update", false, "Record the results of solutions as their golden answers")
+
+// AnswersFile is the name of the golden answer file.
+const AnswersFile = "answers.json"
+
+// Run runs the parts on the inputs in the current directory, which is the
+// package directory when run from a test.  Parts are numbered from 1, and a
+// nil part is skipped.
+//
+// Each part must be a function like part1(t *testing.T, in string) R, where
+// the first parameter can be any type that *testing.T is assignable to (such
+// as advent.OptionalT) and R can be any type that can be stored as JSON.
+func Run(t *testing.T, parts ...interface{}) {
+	t.Helper()
+	h := &Harness{Dir: ".", Update: *update}
+	h.Run(t, parts...)
+}
+
+// A Harness runs solutions against the inputs and answers in a directory.
+type Harness struct {
+	Dir    string // directory containing the inputs and answers
+	Update bool   // if true, record results instead of comparing them
+}
+
+// Answers holds the golden answers, keyed by part ("part1") and then by the
+// name of the input file.
+type Answers map[string]map[string]json.RawMessage
+
+// Run is like the package-level Run but uses the harness's settings.
+func (h *Harness) Run(t *testing.T, parts ...interface{}) {
+	t.Helper()
+
+	answers, err := h.loadAnswers()
+	if err != nil {
+		t.Fatalf("loading answers: %s", err)
+	}
+	inputs, err := h.inputs(answers)
+	if err != nil {
+		t.Fatalf("finding inputs: %s", err)
+	}
+
+	changed := false
+	for i, part := range parts {
+		if part == nil {
+			continue
+		}
+		solve := solver(t, part)
+		partName := fmt.Sprintf("part%d", i+1)
+		t.Run(partName, func(t *testing.T) {
+			for _, name := range inputs {
+				want, ok := answers[partName][name]
+				if !ok && !h.Update {
+					continue
+				}
+				t.Run(name, func(t *testing.T) {
+					in := advent.ReadFile(t, filepath.Join(h.Dir, name))
+					got := solve(t, in)
+
+					if h.Update {
+						js, err := json.Marshal(got)
+						if err != nil {
+							t.Fatalf("%s(%s) returned %#v, which cannot be recorded: %s", partName, name, got, err)
+						}
+						if !bytes.Equal(js, want) {
+							t.Logf("%s(%s): recording %s (was %s)", partName, name, js, orNone(want))
+							if answers[partName] == nil {
+								answers[partName] = make(map[string]json.RawMessage)
+							}
+							answers[partName][name] = js
+							changed = true
+						}
+						return
+					}
+
+					diff, err := Diff(got, want)
+					if err != nil {
+						t.Fatalf("%s(%s): %s", partName, name, err)
+					}
+					if diff != "" {
+						t.Errorf("%s(%s) returned incorrect result: (-got +want)\n%s", partName, name, diff)
+					}
+				})
+			}
+		})
+	}
+
+	if changed {
+		if err := h.saveAnswers(answers); err != nil {
+			t.Fatalf("saving answers: %s", err)
+		}
+	}
+}
+
+func orNone(js json.RawMessage) string {
+	if js == nil {
+		return "none"
+	}
+	return string(js)
+}
+
+// Diff returns a human-readable difference between got and the golden answer,
+// which is decoded into the same type as got for the comparison.  The result
+// is empty if they are equal.
+func Diff(got interface{}, want json.RawMessage) (string, error) {
+	if got == nil {
+		if string(want) == "null" {
+			return "", nil
+		}
+		return cmp.Diff(nil, want), nil
+	}
+	typ := reflect.TypeOf(got)
+	wantVal := reflect.New(typ)
+	if err := json.Unmarshal(want, wantVal.Interface()); err != nil {
+		return "", fmt.Errorf("answer %s cannot be decoded as %T: %s", want, got, err)
+	}
+	return cmp.Diff(got, wantVal.Elem().Interface()), nil
+}
+
+// solver converts a part function into a uniform signature.
+func solver(t *testing.T, part interface{}) func(t *testing.T, in string) interface{} {
+	t.Helper()
+	fval := reflect.ValueOf(part)
+	ftyp := fval.Type()
+	if ftyp.Kind() != reflect.Func || ftyp.NumIn() != 2 || ftyp.NumOut() != 1 ||
+		!reflect.TypeOf(t).AssignableTo(ftyp.In(0)) || ftyp.In(1).Kind() != reflect.String {
+		t.Fatalf("got part %T, want func(*testing.T, string) R", part)
+	}
+	return func(t *testing.T, in string) interface{} {
+		out := fval.Call([]reflect.Value{reflect.ValueOf(t), reflect.ValueOf(in).Convert(ftyp.In(1))})
+		return out[0].Interface()
+	}
+}
+
+// inputs returns the names of the input files, in the order they should be run.
+func (h *Harness) inputs(answers Answers) ([]string, error) {
+	examples, err := filepath.Glob(filepath.Join(h.Dir, "example*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, path := range examples {
+		names = append(names, filepath.Base(path))
+	}
+	sort.Strings(names)
+
+	// The input may not exist locally (advent.ReadFile will check the cache),
+	// so it is included if it exists or if an answer has been recorded.
+	const input = "input.txt"
+	_, err = os.Stat(filepath.Join(h.Dir, input))
+	hasAnswer := false
+	for _, byInput := range answers {
+		if _, ok := byInput[input]; ok {
+			hasAnswer = true
+		}
+	}
+	if err == nil || hasAnswer {
+		names = append(names, input)
+	}
+	return names, nil
+}
+
+func (h *Harness) loadAnswers() (Answers, error) {
+	answers := make(Answers)
+	data, err := ioutil.ReadFile(filepath.Join(h.Dir, AnswersFile))
+	if os.IsNotExist(err) {
+		return answers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &answers); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", AnswersFile, err)
+	}
+	return answers, nil
+}
+
+func (h *Harness) saveAnswers(answers Answers) error {
+	// Maps are marshaled with sorted keys, so the file is deterministic.
+	data, err := json.MarshalIndent(answers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(h.Dir, AnswersFile), append(data, '\n'), 0644)
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aoctest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kylelemons/adventofcodesolutions/advent"
+)
+
+func sum(t advent.OptionalT, in string) (ret int) {
+	advent.Lines(in).Scan(t, func(v int) { ret += v })
+	return
+}
+
+func sorted(t *testing.T, in string) []string {
+	lines := advent.Lines(in).All(t)
+	for i := 1; i < len(lines); i++ {
+		for j := i; j > 0 && lines[j] < lines[j-1]; j-- {
+			lines[j], lines[j-1] = lines[j-1], lines[j]
+		}
+	}
+	return lines
+}
+
+func TestHarness(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("example1.txt", "1\n2\n3\n")
+	write("example2.txt", "30\n20\n10\n")
+	write("input.txt", "3\n1\n2\n")
+	write("notes.txt", "not an input\n")
+
+	h := &Harness{Dir: dir, Update: true}
+	h.Run(t, sum, sorted)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, AnswersFile))
+	if err != nil {
+		t.Fatalf("reading answers: %s", err)
+	}
+	var got map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("parsing answers: %s", err)
+	}
+	want := map[string]map[string]interface{}{
+		"part1": {"example1.txt": 6.0, "example2.txt": 60.0, "input.txt": 6.0},
+		"part2": {
+			"example1.txt": []interface{}{"1", "2", "3"},
+			"example2.txt": []interface{}{"10", "20", "30"},
+			"input.txt":    []interface{}{"1", "2", "3"},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("recorded answers differ: (-got +want)\n%s", diff)
+	}
+
+	// Now that the answers are recorded, they should be checked.
+	h.Update = false
+	h.Run(t, sum, sorted)
+
+	// Inputs without answers are skipped.
+	write(AnswersFile, `{"part2": {"example2.txt": ["10", "20", "30"]}}`)
+	h.Run(t, func(t *testing.T, in string) int {
+		t.Fatalf("part1 should not be run without answers")
+		return 0
+	}, sorted)
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		got     interface{}
+		want    string
+		diff    bool
+		wantErr string
+	}{
+		{"int equal", 42, `42`, false, ""},
+		{"int differ", 41, `42`, true, ""},
+		{"string", "ABC", `"ABC"`, false, ""},
+		{"slice differ", []string{"#..#", "####"}, `["#..#", "#..#"]`, true, ""},
+		{"struct", struct{ X, Y int }{1, 2}, `{"X": 1, "Y": 2}`, false, ""},
+		{"nil", nil, `null`, false, ""},
+		{"wrong type", 42, `"forty-two"`, false, "cannot be decoded as int"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diff, err := Diff(test.got, json.RawMessage(test.want))
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("Diff(%#v, %s) error = %v, want %q", test.got, test.want, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Diff(%#v, %s): %s", test.got, test.want, err)
+			}
+			if got, want := diff != "", test.diff; got != want {
+				t.Errorf("Diff(%#v, %s) = %q, want non-empty=%v", test.got, test.want, diff, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"strings"
+)
+
+// A Compression represents a sequence of tokens as a main routine which calls
+// a small number of functions, each of which is a subsequence of the tokens.
+type Compression struct {
+	Main      []int      // indices into Functions, in the order they are called
+	Functions [][]string // the tokens in each function
+}
+
+// Expand returns the tokens represented by the compression.
+func (c Compression) Expand() []string {
+	var tokens []string
+	for _, f := range c.Main {
+		tokens = append(tokens, c.Functions[f]...)
+	}
+	return tokens
+}
+
+// Routines returns the main routine followed by each function, encoded as
+// comma-separated lists.  Functions are named with letters, so the main
+// routine looks like "A,B,A,C".
+func (c Compression) Routines() []string {
+	var calls []string
+	for _, f := range c.Main {
+		calls = append(calls, string(rune('A'+f)))
+	}
+	routines := []string{strings.Join(calls, ",")}
+	for _, body := range c.Functions {
+		routines = append(routines, strings.Join(body, ","))
+	}
+	return routines
+}
+
+// Compress finds a way to represent tokens as a main routine which calls at
+// most the given number of functions, where the main routine and each of the
+// functions is at most maxLen bytes when encoded as a comma-separated list.
+//
+// The compression always has exactly the given number of functions; any which
+// are not needed are empty.
+//
+// The search is exhaustive, so if ok is false there is no such compression.
+// Longer functions are tried first, so the first compression found tends to
+// have a short main routine.
+//
+// This is the movement function puzzle from 2019 day 17.
+func Compress(tokens []string, functions, maxLen int) (c Compression, ok bool) {
+	maxCalls := (maxLen + 1) / 2 // "A,B,C" has a comma between each call
+
+	// encoded returns the length of tokens[start:end] as a routine.
+	encoded := func(start, end int) int {
+		n := end - start - 1 // commas
+		for _, tok := range tokens[start:end] {
+			n += len(tok)
+		}
+		return n
+	}
+	matches := func(start int, body []string) bool {
+		if start+len(body) > len(tokens) {
+			return false
+		}
+		for i, tok := range body {
+			if tokens[start+i] != tok {
+				return false
+			}
+		}
+		return true
+	}
+
+	var search func(start int) bool
+	search = func(start int) bool {
+		if start == len(tokens) {
+			return true
+		}
+		if len(c.Main) == maxCalls {
+			return false
+		}
+
+		for f, body := range c.Functions {
+			if !matches(start, body) {
+				continue
+			}
+			c.Main = append(c.Main, f)
+			if search(start + len(body)) {
+				return true
+			}
+			c.Main = c.Main[:len(c.Main)-1]
+		}
+
+		if len(c.Functions) == functions {
+			return false
+		}
+		end := start + 1
+		for end < len(tokens) && encoded(start, end+1) <= maxLen {
+			end++
+		}
+	lengths:
+		for ; end > start; end-- {
+			if encoded(start, end) > maxLen {
+				continue
+			}
+			for _, body := range c.Functions {
+				if len(body) == end-start && matches(start, body) {
+					continue lengths // already tried above
+				}
+			}
+			c.Main = append(c.Main, len(c.Functions))
+			c.Functions = append(c.Functions, tokens[start:end:end])
+			if search(end) {
+				return true
+			}
+			c.Functions = c.Functions[:len(c.Functions)-1]
+			c.Main = c.Main[:len(c.Main)-1]
+		}
+		return false
+	}
+
+	if !search(0) {
+		return Compression{}, false
+	}
+	for len(c.Functions) < functions {
+		c.Functions = append(c.Functions, []string{})
+	}
+	return c, true
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompress(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokens    string
+		functions int
+		maxLen    int
+		want      []string // routines, or nil if there is no compression
+	}{
+		{
+			name:      "example",
+			tokens:    "R,8,R,8,R,4,R,4,R,8,L,6,L,2,R,4,R,4,R,8,R,8,R,8,L,6,L,2",
+			functions: 3,
+			maxLen:    20,
+			want:      []string{"A,B,C", "R,8,R,8,R,4,R,4,R,8", "L,6,L,2,R,4,R,4,R,8", "R,8,R,8,L,6,L,2"},
+		},
+		{
+			// A greedy choice of the most common substring doesn't work here.
+			name:      "day17",
+			tokens:    "R,4,L,12,L,8,R,4,L,8,R,10,R,10,R,6,R,4,L,12,L,8,R,4,R,4,R,10,L,12,R,4,L,12,L,8,R,4,L,8,R,10,R,10,R,6,R,4,L,12,L,8,R,4,R,4,R,10,L,12,L,8,R,10,R,10,R,6,R,4,R,10,L,12",
+			functions: 3,
+			maxLen:    20,
+			want:      []string{"A,B,A,C,A,B,A,C,B,C", "R,4,L,12,L,8,R,4", "L,8,R,10,R,10,R,6", "R,4,R,10,L,12"},
+		},
+		{
+			name:      "fewer functions",
+			tokens:    "a,b,a,b,a,b",
+			functions: 3,
+			maxLen:    20,
+			want:      []string{"A", "a,b,a,b,a,b", "", ""},
+		},
+		{
+			// The robot in 2019 day 17 still expects all three functions.
+			name:      "one function",
+			tokens:    "R,8,R,8,R,8",
+			functions: 3,
+			maxLen:    20,
+			want:      []string{"A", "R,8,R,8,R,8", "", ""},
+		},
+		{
+			name:      "short functions",
+			tokens:    "a,b,a,b,a,b",
+			functions: 3,
+			maxLen:    5,
+			want:      []string{"A,B", "a,b,a", "b,a,b", ""},
+		},
+		{
+			name:      "too many functions",
+			tokens:    "a,b,c,d,e,f,g",
+			functions: 2,
+			maxLen:    5,
+		},
+		{
+			name:      "main too long",
+			tokens:    "a,a,a,a,a,a,a,a,a,a,a",
+			functions: 1,
+			maxLen:    20,
+		},
+		{
+			name:      "impossible",
+			tokens:    "a,b,a,c,a,d,a,e",
+			functions: 2,
+			maxLen:    3,
+		},
+		{
+			name:      "token too long",
+			tokens:    "abcd",
+			functions: 3,
+			maxLen:    3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens := strings.Split(test.tokens, ",")
+			c, ok := Compress(tokens, test.functions, test.maxLen)
+			if got, want := ok, test.want != nil; got != want {
+				t.Fatalf("Compress(%q) ok = %v, want %v", test.tokens, got, want)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(c.Routines(), test.want); diff != "" {
+				t.Errorf("Compress(%q) differs: (-got +want)\n%s", test.tokens, diff)
+			}
+			if diff := cmp.Diff(c.Expand(), tokens); diff != "" {
+				t.Errorf("Expand() differs: (-got +want)\n%s", diff)
+			}
+			for _, routine := range c.Routines() {
+				if len(routine) > test.maxLen {
+					t.Errorf("routine %q is longer than %d", routine, test.maxLen)
+				}
+			}
+		})
+	}
+}
+
+func ExampleCompress() {
+	path := strings.Split("L,4,R,8,L,4,R,8,R,2,R,2,L,4,R,8", ",")
+	c, ok := Compress(path, 2, 8)
+	fmt.Println(ok)
+	for _, routine := range c.Routines() {
+		fmt.Println(routine)
+	}
+	// Output:
+	// true
+	// A,A,B,A
+	// L,4,R,8
+	// R,2,R,2
+}
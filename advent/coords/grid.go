@@ -0,0 +1,88 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coords
+
+import (
+	"strings"
+)
+
+// Find2D returns the coordinates of every occurrence of b in the grid, in
+// row-major order.
+func Find2D(grid [][]byte, b byte) []Coord {
+	return FindAny2D(grid, string(b))
+}
+
+// FindAny2D returns the coordinates of every cell in the grid which contains
+// any of the bytes in set, in row-major order.
+func FindAny2D(grid [][]byte, set string) []Coord {
+	var found []Coord
+	for r, row := range grid {
+		for c, b := range row {
+			if strings.IndexByte(set, b) >= 0 {
+				found = append(found, RC(r, c))
+			}
+		}
+	}
+	return found
+}
+
+// Index2D returns the coordinates of every byte in the grid except those in
+// ignore (like "#." for walls and open floor), each in row-major order.
+func Index2D(grid [][]byte, ignore string) map[byte][]Coord {
+	index := make(map[byte][]Coord)
+	for r, row := range grid {
+		for c, b := range row {
+			if strings.IndexByte(ignore, b) < 0 {
+				index[b] = append(index[b], RC(r, c))
+			}
+		}
+	}
+	return index
+}
+
+// Arrows are the bytes which are used to draw the cardinal directions, in
+// the same order as Cardinals.
+const Arrows = "^>v<"
+
+// ParseArrow returns the direction indicated by an arrow like '^', and
+// whether b is an arrow.
+func ParseArrow(b byte) (Vector, bool) {
+	if i := strings.IndexByte(Arrows, b); i >= 0 {
+		return Cardinals[i], true
+	}
+	return Vector{}, false
+}
+
+// Arrow returns the arrow which points in the given cardinal direction, or 0
+// if v is not a cardinal direction.
+func Arrow(v Vector) byte {
+	for i, dir := range Cardinals {
+		if v == dir {
+			return Arrows[i]
+		}
+	}
+	return 0
+}
+
+// FindArrow2D returns the position and direction of the first arrow in the
+// grid (such as a robot), in row-major order, and whether there is one.
+func FindArrow2D(grid [][]byte) (Coord, Vector, bool) {
+	found := FindAny2D(grid, Arrows)
+	if len(found) == 0 {
+		return Coord{}, Vector{}, false
+	}
+	dir, _ := ParseArrow(found[0].In2D(grid))
+	return found[0], dir, true
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coords
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const vault = `#########
+#b.A.@.a#
+#########`
+
+func split(s string) [][]byte {
+	var grid [][]byte
+	for _, row := range strings.Split(s, "\n") {
+		grid = append(grid, []byte(row))
+	}
+	return grid
+}
+
+func TestFind2D(t *testing.T) {
+	grid := split(vault)
+	if got, want := Find2D(grid, '@'), []Coord{RC(1, 5)}; !cmp.Equal(got, want, cmp.AllowUnexported(Coord{})) {
+		t.Errorf("Find2D(@) = %v, want %v", got, want)
+	}
+	if got, want := FindAny2D(grid, "ab"), []Coord{RC(1, 1), RC(1, 7)}; !cmp.Equal(got, want, cmp.AllowUnexported(Coord{})) {
+		t.Errorf("FindAny2D(ab) = %v, want %v", got, want)
+	}
+	if got := Find2D(grid, 'z'); got != nil {
+		t.Errorf("Find2D(z) = %v, want none", got)
+	}
+}
+
+func TestIndex2D(t *testing.T) {
+	got := Index2D(split(vault), "#.")
+	want := map[byte][]Coord{
+		'@': {RC(1, 5)},
+		'a': {RC(1, 7)},
+		'b': {RC(1, 1)},
+		'A': {RC(1, 3)},
+	}
+	if diff := cmp.Diff(got, want, cmp.AllowUnexported(Coord{})); diff != "" {
+		t.Errorf("Index2D differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestArrows(t *testing.T) {
+	for i := range Arrows {
+		dir, ok := ParseArrow(Arrows[i])
+		if !ok || dir != Cardinals[i] {
+			t.Errorf("ParseArrow(%q) = %v, %v, want %v, true", Arrows[i], dir, ok, Cardinals[i])
+		}
+		if got, want := Arrow(dir), Arrows[i]; got != want {
+			t.Errorf("Arrow(%v) = %q, want %q", dir, got, want)
+		}
+	}
+	if _, ok := ParseArrow('#'); ok {
+		t.Errorf("ParseArrow('#') succeeded, want failure")
+	}
+	if got := Arrow(NorthEast); got != 0 {
+		t.Errorf("Arrow(NorthEast) = %q, want 0", got)
+	}
+
+	grid := split("..#..\n..#..\n..<..")
+	pos, dir, ok := FindArrow2D(grid)
+	if pos != RC(2, 2) || dir != West || !ok {
+		t.Errorf("FindArrow2D = %v, %v, %v, want %v, %v, true", pos, dir, ok, RC(2, 2), West)
+	}
+	if _, _, ok := FindArrow2D(split(vault)); ok {
+		t.Errorf("FindArrow2D(vault) succeeded, want failure")
+	}
+}
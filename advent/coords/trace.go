@@ -0,0 +1,121 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coords
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A Move is a turn followed by some number of steps forward.
+type Move struct {
+	Turn  byte // 'L' or 'R', or 0 if there was no turn
+	Steps int
+}
+
+// String returns the move like "R,8", or just "8" if there was no turn.
+func (m Move) String() string {
+	if m.Turn == 0 {
+		return strconv.Itoa(m.Steps)
+	}
+	return string(m.Turn) + "," + strconv.Itoa(m.Steps)
+}
+
+// A Path is the result of following a path through a grid.
+type Path struct {
+	Moves   []Move // the run-length encoded turns and steps
+	Letters string // the letters (A-Z) on the path, in the order they were visited
+	Steps   int    // the total number of steps
+
+	End  Coord  // the final position
+	Dir  Vector // the final direction
+	Loop bool   // whether the path stopped because it would repeat itself
+}
+
+// Tokens returns the turns and steps as separate tokens, like "R", "8", "L",
+// "10".
+func (p Path) Tokens() []string {
+	var tokens []string
+	for _, m := range p.Moves {
+		if m.Turn != 0 {
+			tokens = append(tokens, string(m.Turn))
+		}
+		tokens = append(tokens, strconv.Itoa(m.Steps))
+	}
+	return tokens
+}
+
+// String returns the moves like "R,8,L,10".
+func (p Path) String() string {
+	return strings.Join(p.Tokens(), ",")
+}
+
+// Trace follows a path through the grid, starting at start and facing dir,
+// through cells for which passable returns true.
+//
+// The path goes forward whenever it can, so it goes straight through
+// intersections, and otherwise it turns left or right (trying left first).
+// It ends when it can't go forward, left or right, or when it would repeat a
+// position and direction.  Cells outside the grid (which may be ragged) are
+// not passable.
+func Trace(grid [][]byte, start Coord, dir Vector, passable func(byte) bool) Path {
+	open := func(c Coord) bool {
+		b, ok := c.InBounds2D(grid)
+		return ok && passable(b)
+	}
+
+	p := Path{End: start, Dir: dir}
+	visit := func(c Coord) {
+		if b, ok := c.InBounds2D(grid); ok && b >= 'A' && b <= 'Z' {
+			p.Letters += string(b)
+		}
+	}
+	visit(start)
+
+	type state struct {
+		pos Coord
+		dir Vector
+	}
+	seen := map[state]bool{{start, dir}: true}
+	for {
+		var turn byte
+		dir := p.Dir
+		switch {
+		case open(p.End.Add(dir)):
+		case open(p.End.Add(dir.Left())):
+			turn, dir = 'L', dir.Left()
+		case open(p.End.Add(dir.Right())):
+			turn, dir = 'R', dir.Right()
+		default:
+			return p
+		}
+
+		next := state{p.End.Add(dir), dir}
+		if seen[next] {
+			p.Loop = true
+			return p
+		}
+		seen[next] = true
+
+		if last := len(p.Moves) - 1; turn == 0 && last >= 0 {
+			p.Moves[last].Steps++
+		} else {
+			p.Moves = append(p.Moves, Move{Turn: turn, Steps: 1})
+		}
+		p.End, p.Dir = next.pos, next.dir
+		p.Steps++
+		visit(p.End)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coords
+
+import (
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	scaffold := func(b byte) bool { return b == '#' }
+	tubes := func(b byte) bool { return b != ' ' }
+
+	tests := []struct {
+		name     string
+		grid     string
+		start    Coord
+		dir      Vector
+		passable func(byte) bool
+
+		moves   string
+		letters string
+		steps   int
+		end     Coord
+		loop    bool
+	}{
+		{
+			name: "scaffold",
+			grid: `#######...#####
+#.....#...#...#
+#.....#...#...#
+......#...#...#
+......#...###.#
+......#.....#.#
+#########...#.#
+......#.#...#.#
+......#########
+........#...#..
+....#########..
+....#...#......
+....#...#......
+....#...#......
+....#####......`,
+			start:    RC(6, 0),
+			dir:      North,
+			passable: scaffold,
+			moves:    "R,8,R,8,R,4,R,4,R,8,L,6,L,2,R,4,R,4,R,8,R,8,R,8,L,6,L,2",
+			steps:    80,
+			end:      RC(2, 0),
+		},
+		{
+			name: "tubes",
+			grid: `     |          
+     |  +--+    
+     A  |  C    
+ F---|----E|--+ 
+     |  |  |  D 
+     +B-+  +--+ `,
+			start:    RC(0, 5),
+			dir:      South,
+			passable: tubes,
+			moves:    "5,L,3,L,4,R,3,R,4,L,3,L,2,L,13",
+			letters:  "ABCDEF",
+			steps:    37,
+			end:      RC(3, 1),
+		},
+		{
+			name:     "loop",
+			grid:     "###\n#.#\n###",
+			start:    RC(0, 0),
+			dir:      East,
+			passable: scaffold,
+			moves:    "2,R,2,R,2,R,2",
+			steps:    8,
+			end:      RC(0, 0),
+			loop:     true,
+		},
+		{
+			name:     "ragged",
+			grid:     "A-+\n  |\n  B--\n",
+			start:    RC(0, 0),
+			dir:      East,
+			passable: tubes,
+			moves:    "2,R,2,L,2",
+			letters:  "AB",
+			steps:    6,
+			end:      RC(2, 4),
+		},
+		{
+			name:     "stuck",
+			grid:     "#",
+			start:    RC(0, 0),
+			dir:      North,
+			passable: scaffold,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := Trace(split(test.grid), test.start, test.dir, test.passable)
+			if got, want := p.String(), test.moves; got != want {
+				t.Errorf("moves = %q, want %q", got, want)
+			}
+			if got, want := p.Letters, test.letters; got != want {
+				t.Errorf("Letters = %q, want %q", got, want)
+			}
+			if got, want := p.Steps, test.steps; got != want {
+				t.Errorf("Steps = %v, want %v", got, want)
+			}
+			if got, want := p.End, test.end; got != want {
+				t.Errorf("End = %v, want %v", got, want)
+			}
+			if got, want := p.Loop, test.loop; got != want {
+				t.Errorf("Loop = %v, want %v", got, want)
+			}
+		})
+	}
+}
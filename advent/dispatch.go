@@ -0,0 +1,89 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// A Rule pairs a regular expression with the function that should be called
+// with its capture groups when it matches.
+//
+// Func must be a function with one parameter per capture group in Pattern.
+// Captured values are converted to the parameter types in the same way as
+// Scanner.Extract.
+type Rule struct {
+	Pattern string
+	Func    interface{}
+}
+
+// On returns a Rule which calls f when pattern matches.
+func On(pattern string, f interface{}) Rule {
+	return Rule{Pattern: pattern, Func: f}
+}
+
+// Dispatch calls the function for the first rule whose pattern matches each
+// non-empty token.  It is intended for instruction-style inputs with several
+// line formats:
+//
+//	advent.Lines(in).Dispatch(t,
+//		advent.On(`^snd (\w)$`, func(reg string) { ... }),
+//		advent.On(`^set (\w) (-?\d+)$`, func(reg string, val int) { ... }),
+//		advent.On(`^set (\w) (\w)$`, func(dst, src string) { ... }),
+//	)
+//
+// Like Extract, patterns are not implicitly anchored.  A token which matches
+// none of the rules is a fatal error.
+func (d *Delimited) Dispatch(t OptionalT, rules ...Rule) {
+	t = maybeT(t)
+	t.Helper()
+
+	type handler struct {
+		re       *regexp.Regexp
+		fval     reflect.Value
+		pointers []interface{}
+		values   []reflect.Value
+	}
+	handlers := make([]handler, len(rules))
+	for i, rule := range rules {
+		r, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			t.Fatalf("bad regexp %q: %s", rule.Pattern, err)
+		}
+		fval := reflect.ValueOf(rule.Func)
+		if fval.Kind() != reflect.Func {
+			t.Fatalf("rule %d (/%s/): got %T, want a function", i, rule.Pattern, rule.Func)
+		}
+		if got, want := fval.Type().NumIn(), r.NumSubexp(); got != want {
+			t.Fatalf("rule %d (/%s/): function has %d parameters, want %d (number of groups)", i, rule.Pattern, got, want)
+		}
+		pointers, values := inputsFor(fval)
+		handlers[i] = handler{r, fval, pointers, values}
+	}
+
+	d.Each(func(_ int, token Scanner) {
+		for _, h := range handlers {
+			matches := h.re.FindStringSubmatch(string(token))
+			if matches == nil {
+				continue
+			}
+			storeGroups(d.positionT(t), matches[1:], h.pointers)
+			h.fval.Call(h.values)
+			return
+		}
+		d.positionT(t).Fatalf("no rule matches %q", token)
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDispatch(t *testing.T) {
+	in := `
+set a 1
+add a 2
+set b a
+snd a
+jgz a -1
+`
+	var got []string
+	Lines(in).Dispatch(t,
+		On(`^snd (\w)$`, func(reg string) {
+			got = append(got, fmt.Sprintf("snd(%s)", reg))
+		}),
+		On(`^(set|add) (\w) (-?\d+)$`, func(op, reg string, val int) {
+			got = append(got, fmt.Sprintf("%s(%s, %d)", op, reg, val))
+		}),
+		On(`^set (\w) (\w)$`, func(dst, src string) {
+			got = append(got, fmt.Sprintf("set(%s, reg %s)", dst, src))
+		}),
+		On(`^jgz (\w) (-?\d+)$`, func(reg string, off int) {
+			got = append(got, fmt.Sprintf("jgz(%s, %d)", reg, off))
+		}),
+	)
+	want := []string{
+		"set(a, 1)",
+		"add(a, 2)",
+		"set(b, reg a)",
+		"snd(a)",
+		"jgz(a, -1)",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Dispatch produced incorrect calls: (-got +want)\n%s", diff)
+	}
+}
+
+func TestDispatchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		rules []Rule
+		want  string
+	}{
+		{
+			name:  "unmatched",
+			in:    "inc a\ninc b\ndec c",
+			rules: []Rule{On(`^inc (\w)$`, func(string) {})},
+			want:  `line 3: no rule matches "dec c"`,
+		},
+		{
+			name:  "arity",
+			in:    "inc a",
+			rules: []Rule{On(`^inc (\w)$`, func(a, b string) {})},
+			want:  `rule 0 (/^inc (\w)$/): function has 2 parameters, want 1 (number of groups)`,
+		},
+		{
+			name:  "conversion",
+			in:    "cpy 1 a\ncpy x a",
+			rules: []Rule{On(`^cpy (\w) (\w)$`, func(int, string) {})},
+			want:  `line 2: failed to scan "x" into *int: expected integer`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ft := new(fatalT)
+			ft.catch(func() {
+				Lines(test.in).Dispatch(ft, test.rules...)
+			})
+			if got, want := ft.msg, test.want; got != want {
+				t.Errorf("Dispatch failure = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -14,6 +14,12 @@
 
 package advent
 
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
 // Perm calls the given function with all permutaions of [0,n). It will be
 // called exactly n! times.
 //
@@ -68,35 +74,300 @@ func Perm(n int, f func([]int)) {
 	}
 }
 
-// Primes returns successive prime numbers.
-func Primes() func() int {
-	// Save prime numbers that we have seen so far.
-	var primes []int
+// Combinations calls the given function with all k-element subsets of [0,n),
+// each in increasing order, in lexicographic order.  It will be called exactly
+// n-choose-k times.
+//
+// The function will not be called at all for k < 0 or k > n.
+func Combinations(n, k int, f func([]int)) {
+	if k < 0 || k > n {
+		return
+	}
 
-	// Keep track of the next number to try.
-	n := 1 // 0 and 1 are not primes, skip them
-	return func() int {
-	restart:
-		n++
+	combination := make([]int, k) // only allocate once
+	for i := range combination {
+		combination[i] = i
+	}
+	for {
+		f(combination)
 
-		divisor := 2 // don't bother with any divisors below 2
+		// Find the rightmost index which can still be incremented, which
+		// is the one that hasn't reached its maximum of n-k+i.
+		i := k - 1
+		for i >= 0 && combination[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		combination[i]++
+		for j := i + 1; j < k; j++ {
+			combination[j] = combination[j-1] + 1
+		}
+	}
+}
 
-		// Skip through the low numbers by using the existing primes
-		for _, divisor = range primes {
-			if n%divisor == 0 {
-				goto restart
+// Subsets calls the given function with all subsets of [0,n), represented by
+// whether each index is in the subset.  It will be called exactly 2^n times.
+//
+// The subsets are generated in Gray code order, so each subset differs from
+// the previous one by the single index changed, which makes it cheap to keep
+// track of aggregates (like the sum of weights) incrementally.  The first call
+// is the empty subset, for which changed is -1.
+//
+// The function will not be called at all for n < 0.
+func Subsets(n int, f func(in []bool, changed int)) {
+	if n < 0 {
+		return
+	}
+	if n >= 63 {
+		panic(fmt.Sprintf("Subsets(%d): too many subsets", n))
+	}
+
+	in := make([]bool, n) // only allocate once
+	f(in, -1)
+	for i := 1; i < 1<<n; i++ {
+		// The bit that changes between successive Gray codes is the
+		// lowest set bit of the counter.
+		changed := bits.TrailingZeros(uint(i))
+		in[changed] = !in[changed]
+		f(in, changed)
+	}
+}
+
+// PermK calls the given function with all k-permutations of [0,n), that is
+// all ordered selections of k distinct indices, in lexicographic order.  It
+// will be called exactly n!/(n-k)! times.
+//
+// The function will not be called at all for k < 0 or k > n.
+func PermK(n, k int, f func([]int)) {
+	if k < 0 || k > n {
+		return
+	}
+	if k == 0 {
+		f([]int{})
+		return
+	}
+
+	var (
+		permutation = make([]int, k)  // only allocate once
+		used        = make([]bool, n) // whether an index is in permutation
+	)
+	permutation[0] = -1
+	for pos := 0; pos >= 0; {
+		// Advance the index at pos to the next one that isn't in use.
+		if prev := permutation[pos]; prev >= 0 {
+			used[prev] = false
+		}
+		next := permutation[pos] + 1
+		for next < n && used[next] {
+			next++
+		}
+		if next == n {
+			pos-- // exhausted, backtrack
+			continue
+		}
+		permutation[pos], used[next] = next, true
+
+		if pos == k-1 {
+			f(permutation)
+			continue
+		}
+		pos++
+		permutation[pos] = -1
+	}
+}
+
+// CartesianProduct calls the given function with every combination of indices
+// where the ith index is in [0,sizes[i]), in lexicographic order.  It will be
+// called exactly once for each element of the product of the sizes.
+//
+// The function will not be called at all if any size is less than 1.
+func CartesianProduct(sizes []int, f func([]int)) {
+	for _, size := range sizes {
+		if size < 1 {
+			return
+		}
+	}
+
+	indices := make([]int, len(sizes)) // only allocate once
+	for {
+		f(indices)
+
+		// Increment like an odometer, with the last index changing fastest.
+		i := len(indices) - 1
+		for ; i >= 0; i-- {
+			if indices[i]++; indices[i] < sizes[i] {
+				break
 			}
+			indices[i] = 0
+		}
+		if i < 0 {
+			return
 		}
-		divisor++
+	}
+}
 
-		// Manually check other numbers until we get to n/2
-		for ; divisor <= n/2; divisor++ {
-			if n%divisor == 0 {
-				goto restart
+// NextPermutation rearranges v into the lexicographically next permutation of
+// its elements and returns true.  If v is already the last permutation, it is
+// rearranged into the first (sorted) permutation and false is returned.
+//
+// Starting from sorted values, repeatedly calling NextPermutation visits each
+// distinct permutation exactly once, even if some values are repeated.
+func NextPermutation[T Ordered](v []T) bool {
+	// Find the longest non-increasing suffix.
+	i := len(v) - 1
+	for i > 0 && v[i-1] >= v[i] {
+		i--
+	}
+	if i <= 0 {
+		reverse(v)
+		return false
+	}
+
+	// Swap the pivot with the rightmost element greater than it, which
+	// keeps the suffix non-increasing.
+	j := len(v) - 1
+	for v[j] <= v[i-1] {
+		j--
+	}
+	v[i-1], v[j] = v[j], v[i-1]
+	reverse(v[i:])
+	return true
+}
+
+func reverse[T any](v []T) {
+	for i, j := 0, len(v)-1; i < j; i, j = i+1, j-1 {
+		v[i], v[j] = v[j], v[i]
+	}
+}
+
+// Primes returns successive prime numbers.
+//
+// The primes are generated in segments using a sieve, so only the primes up to
+// the square root of the current segment are retained.
+func Primes() func() int {
+	var (
+		base      []int // primes used to sieve the current segment
+		composite = make([]bool, sieveSegmentSize)
+		segment   []int // primes found in the current segment
+		next      = 2   // start of the next segment
+		size      = 256 // size of the next segment, doubled up to sieveSegmentSize
+	)
+	return func() int {
+		for len(segment) == 0 {
+			lo, hi := next, next+size
+			if limit := isqrt(hi - 1); len(base) == 0 || base[len(base)-1] < limit {
+				base = smallPrimes(2 * limit)
+			}
+			segment = sieveSegment(segment[:0:0], lo, hi, base, composite[:size])
+			next = hi
+			if size < sieveSegmentSize {
+				size *= 2
 			}
 		}
+		p := segment[0]
+		segment = segment[1:]
+		return p
+	}
+}
+
+// sieveSegmentSize is the number of candidates sieved at once, which is chosen
+// to keep the working set small enough to stay in cache.
+const sieveSegmentSize = 1 << 15
+
+// PrimesUpTo returns all primes less than or equal to n.
+func PrimesUpTo(n int) []int {
+	return PrimesBetween(2, n+1)
+}
+
+// PrimesBetween returns the primes p with lo <= p < hi in increasing order.
+//
+// It uses a segmented sieve of Eratosthenes, so the memory used is
+// proportional to the square root of hi and the number of primes returned
+// rather than to the width of the range.
+func PrimesBetween(lo, hi int) []int {
+	if lo < 2 {
+		lo = 2
+	}
+	if hi <= lo {
+		return nil
+	}
+	var (
+		base      = smallPrimes(isqrt(hi - 1))
+		composite = make([]bool, sieveSegmentSize)
+		primes    []int
+	)
+	for start := lo; start < hi; start += sieveSegmentSize {
+		end := start + sieveSegmentSize
+		if end > hi {
+			end = hi
+		}
+		primes = sieveSegment(primes, start, end, base, composite[:end-start])
+	}
+	return primes
+}
+
+// sieveSegment appends the primes in [lo, hi) to primes, using base (which must
+// contain every prime up to the square root of hi) to cross off composites.
+//
+// The composite slice is scratch space and must have length hi-lo.  The
+// bounds must satisfy 2 <= lo < hi.
+func sieveSegment(primes []int, lo, hi int, base []int, composite []bool) []int {
+	for i := range composite {
+		composite[i] = false
+	}
+	for _, p := range base {
+		if p*p >= hi {
+			break
+		}
+		first := (lo + p - 1) / p * p
+		if first < p*p {
+			first = p * p
+		}
+		for m := first; m < hi; m += p {
+			composite[m-lo] = true
+		}
+	}
+	for i, c := range composite {
+		if !c {
+			primes = append(primes, lo+i)
+		}
+	}
+	return primes
+}
 
-		primes = append(primes, n)
-		return n
+// smallPrimes returns the primes up to and including n using a simple sieve of
+// Eratosthenes.
+func smallPrimes(n int) []int {
+	if n < 2 {
+		return nil
+	}
+	composite := make([]bool, n+1)
+	var primes []int
+	for i := 2; i <= n; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for m := i * i; m <= n; m += i {
+			composite[m] = true
+		}
+	}
+	return primes
+}
+
+// isqrt returns the largest integer whose square is at most n.
+func isqrt(n int) int {
+	if n < 0 {
+		panic(fmt.Sprintf("isqrt(%d): negative value", n))
+	}
+	r := int(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for (r+1)*(r+1) <= n {
+		r++
 	}
+	return r
 }
@@ -67,6 +67,199 @@ func TestPerm(t *testing.T) {
 	}
 }
 
+func TestCombinations(t *testing.T) {
+	tests := []struct {
+		n, k int
+		v    []string
+	}{
+		{n: 3, k: -1, v: nil},
+		{n: 3, k: 4, v: nil},
+		{n: 0, k: 0, v: []string{"[]"}},
+		{n: 3, k: 0, v: []string{"[]"}},
+		{n: 3, k: 3, v: []string{"[0 1 2]"}},
+		{
+			n: 4,
+			k: 2,
+			v: []string{
+				"[0 1]",
+				"[0 2]",
+				"[0 3]",
+				"[1 2]",
+				"[1 3]",
+				"[2 3]",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("n=%d,k=%d", test.n, test.k), func(t *testing.T) {
+			var out []string
+			Combinations(test.n, test.k, func(v []int) { out = append(out, fmt.Sprint(v)) })
+			if diff := cmp.Diff(out, test.v); diff != "" {
+				t.Errorf("Combinations(%d, %d) produced incorrect results: (-got +want)\n%s", test.n, test.k, diff)
+			}
+		})
+	}
+}
+
+func TestSubsets(t *testing.T) {
+	tests := []struct {
+		n int
+		v []string
+	}{
+		{n: -1, v: nil},
+		{n: 0, v: []string{"[] -1"}},
+		{
+			n: 3,
+			v: []string{
+				"[false false false] -1",
+				"[true false false] 0",
+				"[true true false] 1",
+				"[false true false] 0",
+				"[false true true] 2",
+				"[true true true] 0",
+				"[true false true] 1",
+				"[false false true] 0",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("n=%d", test.n), func(t *testing.T) {
+			var out []string
+			Subsets(test.n, func(in []bool, changed int) { out = append(out, fmt.Sprint(in, changed)) })
+			if diff := cmp.Diff(out, test.v); diff != "" {
+				t.Errorf("Subsets(%d) produced incorrect results: (-got +want)\n%s", test.n, diff)
+			}
+		})
+	}
+}
+
+func TestPermK(t *testing.T) {
+	tests := []struct {
+		n, k int
+		v    []string
+	}{
+		{n: 3, k: -1, v: nil},
+		{n: 2, k: 3, v: nil},
+		{n: 3, k: 0, v: []string{"[]"}},
+		{
+			n: 3,
+			k: 2,
+			v: []string{
+				"[0 1]",
+				"[0 2]",
+				"[1 0]",
+				"[1 2]",
+				"[2 0]",
+				"[2 1]",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("n=%d,k=%d", test.n, test.k), func(t *testing.T) {
+			var out []string
+			PermK(test.n, test.k, func(v []int) { out = append(out, fmt.Sprint(v)) })
+			if diff := cmp.Diff(out, test.v); diff != "" {
+				t.Errorf("PermK(%d, %d) produced incorrect results: (-got +want)\n%s", test.n, test.k, diff)
+			}
+		})
+	}
+
+	// Full permutations should match Perm (in a different order).
+	var perm, permK []string
+	Perm(5, func(v []int) { perm = append(perm, fmt.Sprint(v)) })
+	PermK(5, 5, func(v []int) { permK = append(permK, fmt.Sprint(v)) })
+	sort.Strings(perm)
+	if diff := cmp.Diff(permK, perm); diff != "" {
+		t.Errorf("PermK(5, 5) does not match Perm(5): (-got +want)\n%s", diff)
+	}
+}
+
+func TestCartesianProduct(t *testing.T) {
+	tests := []struct {
+		sizes []int
+		v     []string
+	}{
+		{sizes: nil, v: []string{"[]"}},
+		{sizes: []int{2, 0, 3}, v: nil},
+		{
+			sizes: []int{2, 1, 3},
+			v: []string{
+				"[0 0 0]",
+				"[0 0 1]",
+				"[0 0 2]",
+				"[1 0 0]",
+				"[1 0 1]",
+				"[1 0 2]",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.sizes), func(t *testing.T) {
+			var out []string
+			CartesianProduct(test.sizes, func(v []int) { out = append(out, fmt.Sprint(v)) })
+			if diff := cmp.Diff(out, test.v); diff != "" {
+				t.Errorf("CartesianProduct(%v) produced incorrect results: (-got +want)\n%s", test.sizes, diff)
+			}
+		})
+	}
+}
+
+func TestNextPermutation(t *testing.T) {
+	tests := []struct {
+		start string
+		v     []string
+	}{
+		{start: "", v: []string{""}},
+		{start: "a", v: []string{"a"}},
+		{start: "abc", v: []string{"abc", "acb", "bac", "bca", "cab", "cba"}},
+		{start: "aab", v: []string{"aab", "aba", "baa"}},
+		{start: "bca", v: []string{"bca", "cab", "cba"}},
+	}
+	for _, test := range tests {
+		t.Run(test.start, func(t *testing.T) {
+			v := []byte(test.start)
+			out := []string{string(v)}
+			for NextPermutation(v) {
+				out = append(out, string(v))
+			}
+			if diff := cmp.Diff(out, test.v); diff != "" {
+				t.Errorf("NextPermutation(%q) produced incorrect results: (-got +want)\n%s", test.start, diff)
+			}
+
+			sorted := []byte(test.start)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+			if got, want := string(v), string(sorted); got != want {
+				t.Errorf("after last permutation, got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGeneratorAllocs(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"Perm", func() { Perm(6, func([]int) {}) }},
+		{"Combinations", func() { Combinations(10, 4, func([]int) {}) }},
+		{"Subsets", func() { Subsets(10, func([]bool, int) {}) }},
+		{"PermK", func() { PermK(6, 4, func([]int) {}) }},
+		{"CartesianProduct", func() { CartesianProduct([]int{3, 4, 5}, func([]int) {}) }},
+	}
+	for _, test := range tests {
+		// Allocating the initial state is fine, but there shouldn't be an
+		// allocation per call.
+		if got, max := testing.AllocsPerRun(10, test.f), 2.0; got > max {
+			t.Errorf("%s allocated %v times per run, want at most %v", test.name, got, max)
+		}
+	}
+
+	v := []int{1, 2, 3, 4, 5}
+	if got := testing.AllocsPerRun(100, func() { NextPermutation(v) }); got != 0 {
+		t.Errorf("NextPermutation allocated %v times per run, want 0", got)
+	}
+}
+
 func TestPrimes(t *testing.T) {
 	want := []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}
 	primes := Primes()
@@ -80,6 +273,43 @@ func TestPrimes(t *testing.T) {
 	}
 }
 
+func TestPrimesBetween(t *testing.T) {
+	var want []int
+	primes := Primes()
+	for p := primes(); p < 3*sieveSegmentSize; p = primes() {
+		want = append(want, p)
+	}
+
+	tests := []struct {
+		lo, hi int
+	}{
+		{0, 0},
+		{0, 2},
+		{0, 3},
+		{-10, 30},
+		{14, 17},
+		{sieveSegmentSize - 100, sieveSegmentSize + 100},
+		{0, 3 * sieveSegmentSize},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("[%d,%d)", test.lo, test.hi), func(t *testing.T) {
+			var inRange []int
+			for _, p := range want {
+				if test.lo <= p && p < test.hi {
+					inRange = append(inRange, p)
+				}
+			}
+			if diff := cmp.Diff(PrimesBetween(test.lo, test.hi), inRange); diff != "" {
+				t.Errorf("PrimesBetween(%d, %d) returned incorrect primes: (-got +want)\n%s", test.lo, test.hi, diff)
+			}
+		})
+	}
+
+	if got, want := len(PrimesUpTo(1000000)), 78498; got != want {
+		t.Errorf("len(PrimesUpTo(1000000)) = %v, want %v", got, want)
+	}
+}
+
 func BenchmarkPerm(b *testing.B) {
 	for _, n := range []int{0, 1, 3, 5, 7, 10} {
 		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
@@ -92,6 +322,30 @@ func BenchmarkPerm(b *testing.B) {
 	}
 }
 
+func BenchmarkCombinations(b *testing.B) {
+	for _, n := range []int{5, 10, 20} {
+		b.Run(fmt.Sprintf("n=%d,k=%d", n, n/2), func(b *testing.B) {
+			var total int
+			for i := 0; i < b.N; i++ {
+				Combinations(n, n/2, func(v []int) { total += v[0] })
+			}
+			_ = total
+		})
+	}
+}
+
+func BenchmarkSubsets(b *testing.B) {
+	for _, n := range []int{5, 10, 20} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			var total int
+			for i := 0; i < b.N; i++ {
+				Subsets(n, func(in []bool, changed int) { total += changed })
+			}
+			_ = total
+		})
+	}
+}
+
 func BenchmarkPrimes(b *testing.B) {
 	for _, n := range []int{100, 1000, 10000} {
 		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
@@ -109,6 +363,45 @@ func BenchmarkPrimes(b *testing.B) {
 	}
 }
 
+func BenchmarkPrimesUpTo(b *testing.B) {
+	for _, n := range []int{1000, 100000, 10000000} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			var count int
+			for i := 0; i < b.N; i++ {
+				count += len(PrimesUpTo(n))
+			}
+			_ = count
+		})
+	}
+}
+
+func BenchmarkIsPrime(b *testing.B) {
+	for _, n := range []int{7919, 2147483647, 2305843009213693951, 998244359987710471} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			var count int
+			for i := 0; i < b.N; i++ {
+				if IsPrime(n) {
+					count++
+				}
+			}
+			_ = count
+		})
+	}
+}
+
+func BenchmarkFactorize(b *testing.B) {
+	for _, n := range []int{12300, 100200300400, 4611686014132420609, 998244359987710471} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			var count int
+			for i := 0; i < b.N; i++ {
+				count += len(Factorize(n))
+			}
+			_ = count
+		})
+	}
+}
+
 func ExamplePerm() {
 	colors := []string{
 		"red",
@@ -132,3 +425,30 @@ func ExamplePerm() {
 	// blue, green, red
 	// green, blue, red
 }
+
+func ExampleSubsets() {
+	weights := []int{4, 2, 1}
+
+	// Keep a running total instead of summing each subset.
+	var total int
+	Subsets(len(weights), func(in []bool, changed int) {
+		switch {
+		case changed < 0:
+		case in[changed]:
+			total += weights[changed]
+		default:
+			total -= weights[changed]
+		}
+		fmt.Println(in, total)
+	})
+
+	// Output:
+	// [false false false] 0
+	// [true false false] 4
+	// [true true false] 6
+	// [false true false] 2
+	// [false true true] 3
+	// [true true true] 7
+	// [true false true] 5
+	// [false false true] 1
+}
@@ -0,0 +1,280 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package input downloads and caches Advent of Code puzzle inputs.
+//
+// Inputs are personalized, so downloading them requires the value of the
+// "session" cookie from a logged-in browser, which is read from the
+// AOC_SESSION environment variable.  Downloaded inputs are cached (by default
+// in the user's cache directory, or in AOC_CACHE if it is set) so that each
+// input is only downloaded once.
+package input
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Environment variables used by FromEnv.
+const (
+	SessionEnv = "AOC_SESSION" // session cookie value
+	CacheEnv   = "AOC_CACHE"   // cache directory
+)
+
+// DefaultBaseURL is the base URL of the Advent of Code website.
+const DefaultBaseURL = "https://adventofcode.com"
+
+// UserAgent identifies this tool to the Advent of Code servers, as requested
+// by the site's maintainers for automated requests.
+const UserAgent = "github.com/kylelemons/adventofcodesolutions/advent/input"
+
+// ErrNoSession is returned when an input must be downloaded but no session
+// cookie has been provided.
+var ErrNoSession = errors.New("no session cookie (set " + SessionEnv + ")")
+
+// A Client downloads and caches puzzle inputs.
+type Client struct {
+	BaseURL  string // website to download from (default: DefaultBaseURL)
+	Session  string // value of the session cookie
+	CacheDir string // directory in which to cache inputs (if empty, disables caching)
+
+	// HTTPClient is used to make requests (default: http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+// FromEnv returns a Client configured from the environment.
+func FromEnv() *Client {
+	c := &Client{
+		Session:  strings.TrimSpace(os.Getenv(SessionEnv)),
+		CacheDir: os.Getenv(CacheEnv),
+	}
+	if c.CacheDir == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			c.CacheDir = filepath.Join(dir, "adventofcode")
+		}
+	}
+	return c
+}
+
+// CachePath returns the path at which the input for the given day is cached.
+//
+// The returned path is empty if caching is disabled.
+func (c *Client) CachePath(year, day int) string {
+	if c.CacheDir == "" {
+		return ""
+	}
+	return filepath.Join(c.CacheDir, strconv.Itoa(year), fmt.Sprintf("day%02d", day), "input.txt")
+}
+
+// Cached returns the cached input for the given day, if any.
+func (c *Client) Cached(year, day int) (input string, ok bool) {
+	path := c.CachePath(year, day)
+	if path == "" {
+		return "", false
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Get returns the input for the given day, downloading and caching it if it
+// has not been cached already.
+func (c *Client) Get(year, day int) (string, error) {
+	if input, ok := c.Cached(year, day); ok {
+		return input, nil
+	}
+	input, err := c.Fetch(year, day)
+	if err != nil {
+		return "", err
+	}
+	if err := c.Store(year, day, input); err != nil {
+		return "", err
+	}
+	return input, nil
+}
+
+// Store writes the input for the given day to the cache, replacing any
+// previously cached input.  The cached file is replaced atomically, so it is
+// never left partially written.
+//
+// Store does nothing if caching is disabled.
+func (c *Client) Store(year, day int, input string) error {
+	path := c.CachePath(year, day)
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating cache: %w", err)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "input-*.tmp")
+	if err != nil {
+		return fmt.Errorf("caching input: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly after the rename
+	if _, err := tmp.WriteString(input); err != nil {
+		tmp.Close()
+		return fmt.Errorf("caching input: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("caching input: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("caching input: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("caching input: %w", err)
+	}
+	return nil
+}
+
+// Fetch downloads the input for the given day, bypassing the cache.
+func (c *Client) Fetch(year, day int) (string, error) {
+	if err := checkDay(year, day); err != nil {
+		return "", err
+	}
+	if c.Session == "" {
+		return "", ErrNoSession
+	}
+
+	req, err := c.NewRequest("GET", c.URL("/%d/day/%d/input", year, day), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching %d day %d: %w", year, day, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading %d day %d: %w", year, day, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching %d day %d: %s: %s", year, day, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return string(body), nil
+}
+
+// Puzzle downloads the HTML puzzle description for the given day.
+//
+// The session cookie is sent if it is set, in which case the description will
+// include part 2 once part 1 has been solved.
+func (c *Client) Puzzle(year, day int) (string, error) {
+	if err := checkDay(year, day); err != nil {
+		return "", err
+	}
+	req, err := c.NewRequest("GET", c.URL("/%d/day/%d", year, day), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.Session == "" {
+		req.Header.Del("Cookie")
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching %d day %d puzzle: %w", year, day, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading %d day %d puzzle: %w", year, day, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching %d day %d puzzle: %s", year, day, resp.Status)
+	}
+	return string(body), nil
+}
+
+var (
+	codeBlockRE = regexp.MustCompile(`(?s)<pre><code>(.*?)</code></pre>`)
+	htmlTagRE   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Examples returns the contents of the <pre><code> blocks in a puzzle
+// description, which usually contain the example inputs, with any markup
+// removed and trailing newlines trimmed.
+func Examples(puzzle string) []string {
+	var examples []string
+	for _, m := range codeBlockRE.FindAllStringSubmatch(puzzle, -1) {
+		text := htmlTagRE.ReplaceAllString(m[1], "")
+		examples = append(examples, strings.TrimRight(html.UnescapeString(text), "\n"))
+	}
+	return examples
+}
+
+// NewRequest returns an HTTP request with the session cookie and user agent
+// set.  The url should be absolute; see URL.
+func (c *Client) NewRequest(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	return req, nil
+}
+
+// URL returns the absolute URL for the formatted path on the website.
+func (c *Client) URL(pathFormat string, args ...interface{}) string {
+	base := DefaultBaseURL
+	if c.BaseURL != "" {
+		base = strings.TrimRight(c.BaseURL, "/")
+	}
+	return base + fmt.Sprintf(pathFormat, args...)
+}
+
+// Do sends the request using the client's HTTPClient.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.HTTPClient == nil {
+		return http.DefaultClient.Do(req)
+	}
+	return c.HTTPClient.Do(req)
+}
+
+func checkDay(year, day int) error {
+	if year < 2015 {
+		return fmt.Errorf("invalid year %d: the first Advent of Code was in 2015", year)
+	}
+	if day < 1 || day > 25 {
+		return fmt.Errorf("invalid day %d: must be between 1 and 25", day)
+	}
+	return nil
+}
+
+var dayDir = regexp.MustCompile(`(?:^|/)(\d{4})/day(\d{1,2})$`)
+
+// DayFromPath returns the year and day for a solution directory, which is
+// expected to be named like ".../2019/day18".
+func DayFromPath(dir string) (year, day int, ok bool) {
+	m := dayDir.FindStringSubmatch(filepath.ToSlash(filepath.Clean(dir)))
+	if m == nil {
+		return 0, 0, false
+	}
+	year, _ = strconv.Atoi(m[1])
+	day, _ = strconv.Atoi(m[2])
+	return year, day, checkDay(year, day) == nil
+}
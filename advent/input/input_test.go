@@ -0,0 +1,176 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeSite returns a server which serves inputs for the "secret" session,
+// along with a pointer to the number of requests it has served.
+func fakeSite(t *testing.T) (*httptest.Server, *int) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got, want := r.Header.Get("User-Agent"), UserAgent; got != want {
+			t.Errorf("User-Agent = %q, want %q", got, want)
+		}
+		if c, err := r.Cookie("session"); err != nil || c.Value != "secret" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+		var year, day int
+		if _, err := fmt.Sscanf(r.URL.Path, "/%d/day/%d/input", &year, &day); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "input for %d day %d\n", year, day)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestGet(t *testing.T) {
+	srv, requests := fakeSite(t)
+	c := &Client{
+		BaseURL:  srv.URL,
+		Session:  "secret",
+		CacheDir: t.TempDir(),
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.Get(2019, 18)
+		if err != nil {
+			t.Fatalf("Get #%d: %s", i, err)
+		}
+		if want := "input for 2019 day 18\n"; got != want {
+			t.Errorf("Get #%d = %q, want %q", i, got, want)
+		}
+	}
+	if got, want := *requests, 1; got != want {
+		t.Errorf("served %d requests, want %d", got, want)
+	}
+
+	if _, ok := c.Cached(2019, 17); ok {
+		t.Errorf("Cached(2019, 17) returned an input that was never fetched")
+	}
+}
+
+func TestStore(t *testing.T) {
+	c := &Client{CacheDir: t.TempDir()}
+	for _, in := range []string{"old\n", "new\n"} {
+		if err := c.Store(2019, 18, in); err != nil {
+			t.Fatalf("Store(%q): %s", in, err)
+		}
+		if got, ok := c.Cached(2019, 18); !ok || got != in {
+			t.Errorf("Cached after Store(%q) = %q, %v, want %q, true", in, got, ok, in)
+		}
+	}
+
+	// A failed download leaves the cached input alone.
+	srv, _ := fakeSite(t)
+	c.BaseURL, c.Session = srv.URL, "expired"
+	if _, err := c.Fetch(2019, 18); err == nil {
+		t.Fatalf("Fetch with an expired session succeeded")
+	}
+	if got, _ := c.Cached(2019, 18); got != "new\n" {
+		t.Errorf("Cached after failed Fetch = %q, want %q", got, "new\n")
+	}
+
+	if err := (&Client{}).Store(2019, 18, "x"); err != nil {
+		t.Errorf("Store without a cache: %s", err)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	srv, _ := fakeSite(t)
+
+	tests := []struct {
+		name      string
+		session   string
+		year, day int
+		want      string
+	}{
+		{"no session", "", 2020, 1, ErrNoSession.Error()},
+		{"bad session", "wrong", 2020, 1, "log in"},
+		{"bad day", "secret", 2020, 26, "invalid day 26"},
+		{"bad year", "secret", 2014, 1, "invalid year 2014"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Client{BaseURL: srv.URL, Session: test.session}
+			_, err := c.Fetch(test.year, test.day)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Fetch(%d, %d) error = %v, want error containing %q", test.year, test.day, err, test.want)
+			}
+		})
+	}
+
+	c := &Client{BaseURL: srv.URL}
+	if _, err := c.Get(2020, 1); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Get without session returned %v, want %v", err, ErrNoSession)
+	}
+}
+
+func TestDayFromPath(t *testing.T) {
+	tests := []struct {
+		dir       string
+		year, day int
+		ok        bool
+	}{
+		{"/src/adventofcodesolutions/2019/day18", 2019, 18, true},
+		{"2021/day01/", 2021, 1, true},
+		{"2021/day9", 2021, 9, true},
+		{"/src/advent", 0, 0, false},
+		{"/src/2019/day26", 2019, 26, false},
+		{"skel", 0, 0, false},
+	}
+	for _, test := range tests {
+		year, day, ok := DayFromPath(test.dir)
+		if year != test.year || day != test.day || ok != test.ok {
+			t.Errorf("DayFromPath(%q) = %v, %v, %v, want %v, %v, %v", test.dir, year, day, ok, test.year, test.day, test.ok)
+		}
+	}
+}
+
+func TestExamples(t *testing.T) {
+	puzzle := `<article class="day-desc"><h2>--- Day 9: Smoke Basin ---</h2>
+<p>Consider the following heightmap:</p>
+<pre><code>2<em>1</em>9994321<em>0</em>
+3987894921
+</code></pre>
+<p>The risk level is <code>1</code> plus its height.</p>
+<pre><code>a &lt; b &amp;&amp; c
+</code></pre>
+</article>`
+	want := []string{
+		"2199943210\n3987894921",
+		"a < b && c",
+	}
+	got := Examples(puzzle)
+	if len(got) != len(want) {
+		t.Fatalf("Examples returned %d blocks %q, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Examples[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
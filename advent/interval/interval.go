@@ -0,0 +1,243 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interval implements arithmetic on integer intervals and sets of them.
+//
+// All intervals are stored half-open, as [Lo, Hi), which makes lengths and
+// adjacency simple to compute; use Closed to construct one from inclusive
+// bounds as they usually appear in puzzle input.
+package interval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// An Interval is the half-open range of integers [Lo, Hi).
+//
+// An interval with Hi <= Lo is empty.
+type Interval struct {
+	Lo, Hi int
+}
+
+// HalfOpen returns the interval [lo, hi).
+func HalfOpen(lo, hi int) Interval {
+	return Interval{Lo: lo, Hi: hi}
+}
+
+// Closed returns the interval [lo, hi], which includes both endpoints.
+func Closed(lo, hi int) Interval {
+	return Interval{Lo: lo, Hi: hi + 1}
+}
+
+// Point returns the interval containing only x.
+func Point(x int) Interval {
+	return Interval{Lo: x, Hi: x + 1}
+}
+
+// Empty returns whether the interval contains no integers.
+func (iv Interval) Empty() bool {
+	return iv.Hi <= iv.Lo
+}
+
+// Len returns the number of integers in the interval.
+func (iv Interval) Len() int {
+	if iv.Empty() {
+		return 0
+	}
+	return iv.Hi - iv.Lo
+}
+
+// Last returns the largest integer in the (non-empty) interval.
+func (iv Interval) Last() int {
+	return iv.Hi - 1
+}
+
+// Contains returns whether x is in the interval.
+func (iv Interval) Contains(x int) bool {
+	return iv.Lo <= x && x < iv.Hi
+}
+
+// Overlaps returns whether the intervals have any integers in common.
+func (iv Interval) Overlaps(o Interval) bool {
+	return !iv.Intersect(o).Empty()
+}
+
+// Intersect returns the integers in both intervals, which may be empty.
+func (iv Interval) Intersect(o Interval) Interval {
+	if o.Lo > iv.Lo {
+		iv.Lo = o.Lo
+	}
+	if o.Hi < iv.Hi {
+		iv.Hi = o.Hi
+	}
+	return iv
+}
+
+// String returns the interval in half-open notation, like "[3,7)".
+func (iv Interval) String() string {
+	return fmt.Sprintf("[%d,%d)", iv.Lo, iv.Hi)
+}
+
+// A Set is a set of integers represented as a normalized list of intervals:
+// the intervals are non-empty, sorted, and neither overlap nor touch.
+//
+// The zero value is an empty set.  Sets are values: the operations below
+// return new sets and do not modify their receivers, though Add and Remove
+// update the set in place for convenience.
+type Set struct {
+	intervals []Interval
+}
+
+// New returns the set of integers in any of the given intervals.
+func New(intervals ...Interval) Set {
+	var s Set
+	for _, iv := range intervals {
+		if !iv.Empty() {
+			s.intervals = append(s.intervals, iv)
+		}
+	}
+	sort.Slice(s.intervals, func(i, j int) bool {
+		return s.intervals[i].Lo < s.intervals[j].Lo
+	})
+
+	// Merge overlapping and adjacent intervals in place.
+	merged := s.intervals[:0]
+	for _, iv := range s.intervals {
+		if n := len(merged); n > 0 && iv.Lo <= merged[n-1].Hi {
+			if iv.Hi > merged[n-1].Hi {
+				merged[n-1].Hi = iv.Hi
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	s.intervals = merged
+	return s
+}
+
+// Intervals returns the normalized intervals in the set.
+//
+// The returned slice must not be modified.
+func (s Set) Intervals() []Interval {
+	return s.intervals
+}
+
+// Empty returns whether the set contains no integers.
+func (s Set) Empty() bool {
+	return len(s.intervals) == 0
+}
+
+// Len returns the number of integers in the set.
+func (s Set) Len() int {
+	var total int
+	for _, iv := range s.intervals {
+		total += iv.Len()
+	}
+	return total
+}
+
+// Bounds returns the smallest interval containing the whole set.
+func (s Set) Bounds() Interval {
+	if s.Empty() {
+		return Interval{}
+	}
+	return Interval{Lo: s.intervals[0].Lo, Hi: s.intervals[len(s.intervals)-1].Hi}
+}
+
+// Contains returns whether x is in the set.
+func (s Set) Contains(x int) bool {
+	// Find the first interval that ends after x.
+	i := sort.Search(len(s.intervals), func(i int) bool {
+		return s.intervals[i].Hi > x
+	})
+	return i < len(s.intervals) && s.intervals[i].Contains(x)
+}
+
+// Union returns the integers in either set.
+func (s Set) Union(o Set) Set {
+	all := make([]Interval, 0, len(s.intervals)+len(o.intervals))
+	all = append(all, s.intervals...)
+	all = append(all, o.intervals...)
+	return New(all...)
+}
+
+// Intersect returns the integers in both sets.
+func (s Set) Intersect(o Set) Set {
+	var out Set
+	a, b := s.intervals, o.intervals
+	for len(a) > 0 && len(b) > 0 {
+		if iv := a[0].Intersect(b[0]); !iv.Empty() {
+			out.intervals = append(out.intervals, iv)
+		}
+		// Drop whichever interval ends first, since it can't overlap
+		// anything else in the other set.
+		if a[0].Hi < b[0].Hi {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return out
+}
+
+// Difference returns the integers in s which are not in o.
+func (s Set) Difference(o Set) Set {
+	var out Set
+	remove := o.intervals
+	for _, iv := range s.intervals {
+		// Skip the intervals that end before this one starts.
+		for len(remove) > 0 && remove[0].Hi <= iv.Lo {
+			remove = remove[1:]
+		}
+		for _, r := range remove {
+			if r.Lo >= iv.Hi {
+				break
+			}
+			if r.Lo > iv.Lo {
+				out.intervals = append(out.intervals, Interval{Lo: iv.Lo, Hi: r.Lo})
+			}
+			iv.Lo = r.Hi
+		}
+		if !iv.Empty() {
+			out.intervals = append(out.intervals, iv)
+		}
+	}
+	return out
+}
+
+// Complement returns the integers in within which are not in s.
+func (s Set) Complement(within Interval) Set {
+	return New(within).Difference(s)
+}
+
+// Add adds the integers in iv to the set.
+func (s *Set) Add(iv Interval) {
+	*s = s.Union(New(iv))
+}
+
+// Remove removes the integers in iv from the set.
+func (s *Set) Remove(iv Interval) {
+	*s = s.Difference(New(iv))
+}
+
+// String returns the intervals in the set, like "{[0,3) [5,6)}".
+func (s Set) String() string {
+	parts := make([]string, len(s.intervals))
+	for i, iv := range s.intervals {
+		parts[i] = iv.String()
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
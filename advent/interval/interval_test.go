@@ -0,0 +1,175 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interval
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInterval(t *testing.T) {
+	iv := Closed(3, 7)
+	if got, want := iv, HalfOpen(3, 8); got != want {
+		t.Errorf("Closed(3, 7) = %v, want %v", got, want)
+	}
+	if got, want := iv.Len(), 5; got != want {
+		t.Errorf("%v.Len() = %v, want %v", iv, got, want)
+	}
+	if got, want := iv.Last(), 7; got != want {
+		t.Errorf("%v.Last() = %v, want %v", iv, got, want)
+	}
+	for x, want := range map[int]bool{2: false, 3: true, 7: true, 8: false} {
+		if got := iv.Contains(x); got != want {
+			t.Errorf("%v.Contains(%d) = %v, want %v", iv, x, got, want)
+		}
+	}
+	if got, want := iv.Intersect(HalfOpen(5, 20)), HalfOpen(5, 8); got != want {
+		t.Errorf("Intersect = %v, want %v", got, want)
+	}
+	if iv.Overlaps(HalfOpen(8, 10)) {
+		t.Errorf("%v should not overlap adjacent [8,10)", iv)
+	}
+	if got, want := HalfOpen(5, 2).Len(), 0; got != want {
+		t.Errorf("empty Len() = %v, want %v", got, want)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Set
+		want string
+	}{
+		{
+			name: "empty",
+			got:  Set{},
+			want: "{}",
+		},
+		{
+			name: "normalize",
+			got:  New(Closed(5, 8), Closed(0, 2), Closed(4, 7), HalfOpen(10, 10), Point(3)),
+			want: "{[0,9)}",
+		},
+		{
+			name: "normalize disjoint",
+			got:  New(Closed(10, 12), Closed(0, 2), Closed(1, 3)),
+			want: "{[0,4) [10,13)}",
+		},
+		{
+			name: "union",
+			got:  New(HalfOpen(0, 3), HalfOpen(10, 15)).Union(New(HalfOpen(2, 5), HalfOpen(20, 21))),
+			want: "{[0,5) [10,15) [20,21)}",
+		},
+		{
+			name: "intersect",
+			got:  New(HalfOpen(0, 10), HalfOpen(20, 30)).Intersect(New(HalfOpen(5, 25), HalfOpen(28, 40))),
+			want: "{[5,10) [20,25) [28,30)}",
+		},
+		{
+			name: "intersect disjoint",
+			got:  New(HalfOpen(0, 10)).Intersect(New(HalfOpen(10, 20))),
+			want: "{}",
+		},
+		{
+			name: "difference",
+			got:  New(HalfOpen(0, 10), HalfOpen(20, 30)).Difference(New(HalfOpen(2, 4), HalfOpen(6, 22), HalfOpen(25, 26))),
+			want: "{[0,2) [4,6) [22,25) [26,30)}",
+		},
+		{
+			name: "difference everything",
+			got:  New(HalfOpen(0, 10)).Difference(New(HalfOpen(-5, 50))),
+			want: "{}",
+		},
+		{
+			name: "complement",
+			got:  New(Closed(5, 8), Closed(0, 2), Closed(4, 7)).Complement(Closed(0, 9)),
+			want: "{[3,4) [9,10)}",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, want := test.got.String(), test.want; got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetAddRemove(t *testing.T) {
+	var s Set
+	s.Add(Closed(0, 9))
+	s.Remove(Closed(3, 4))
+	s.Add(Point(20))
+	if got, want := s.Intervals(), []Interval{{0, 3}, {5, 10}, {20, 21}}; !cmp.Equal(got, want) {
+		t.Errorf("Intervals() = %v, want %v", got, want)
+	}
+	if got, want := s.Len(), 9; got != want {
+		t.Errorf("Len() = %v, want %v", got, want)
+	}
+	if got, want := s.Bounds(), HalfOpen(0, 21); got != want {
+		t.Errorf("Bounds() = %v, want %v", got, want)
+	}
+	for x, want := range map[int]bool{-1: false, 0: true, 3: false, 4: false, 5: true, 9: true, 10: false, 20: true, 21: false} {
+		if got := s.Contains(x); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestSweep(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []Interval
+		want      []Segment
+	}{
+		{
+			name: "empty",
+			want: nil,
+		},
+		{
+			name:      "nested",
+			intervals: []Interval{{0, 10}, {2, 5}, {3, 4}},
+			want: []Segment{
+				{Interval{0, 2}, 1},
+				{Interval{2, 3}, 2},
+				{Interval{3, 4}, 3},
+				{Interval{4, 5}, 2},
+				{Interval{5, 10}, 1},
+			},
+		},
+		{
+			name:      "gap and handoff",
+			intervals: []Interval{{0, 2}, {5, 7}, {7, 9}, {8, 9}},
+			want: []Segment{
+				{Interval{0, 2}, 1},
+				{Interval{5, 8}, 1},
+				{Interval{8, 9}, 2},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(Sweep(test.intervals...), test.want); diff != "" {
+				t.Errorf("Sweep(%v) returned incorrect segments: (-got +want)\n%s", test.intervals, diff)
+			}
+		})
+	}
+
+	overlap := AtLeast(2, Closed(1, 3), Closed(2, 5), Closed(4, 4), Closed(10, 12))
+	if got, want := overlap.String(), "{[2,5)}"; got != want {
+		t.Errorf("AtLeast(2, ...) = %v, want %v", got, want)
+	}
+}
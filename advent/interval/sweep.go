@@ -0,0 +1,84 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interval
+
+import (
+	"sort"
+)
+
+// A Segment is an interval along with the number of input intervals which
+// cover it.
+type Segment struct {
+	Interval
+	Count int
+}
+
+// Sweep splits the span of the given intervals into maximal segments over
+// which the number of intervals covering each integer is constant, and returns
+// the segments covered by at least one interval in increasing order.
+//
+// This is the classic sweep-line algorithm: each interval contributes an event
+// at each endpoint, and the events are processed in order while keeping a
+// running count.
+func Sweep(intervals ...Interval) []Segment {
+	type event struct {
+		at    int
+		delta int
+	}
+	events := make([]event, 0, 2*len(intervals))
+	for _, iv := range intervals {
+		if iv.Empty() {
+			continue
+		}
+		events = append(events, event{iv.Lo, +1}, event{iv.Hi, -1})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].at < events[j].at
+	})
+
+	var (
+		segments []Segment
+		count    int // number of intervals covering [start, at)
+		start    int
+	)
+	for i := 0; i < len(events); {
+		at := events[i].at
+		if count > 0 {
+			if n := len(segments); n > 0 && segments[n-1].Hi == start && segments[n-1].Count == count {
+				// An interval ended where another began.
+				segments[n-1].Hi = at
+			} else {
+				segments = append(segments, Segment{Interval{Lo: start, Hi: at}, count})
+			}
+		}
+		for ; i < len(events) && events[i].at == at; i++ {
+			count += events[i].delta
+		}
+		start = at
+	}
+	return segments
+}
+
+// AtLeast returns the integers covered by at least n of the given intervals,
+// where n is positive.
+func AtLeast(n int, intervals ...Interval) Set {
+	var covered []Interval
+	for _, seg := range Sweep(intervals...) {
+		if seg.Count >= n {
+			covered = append(covered, seg.Interval)
+		}
+	}
+	return New(covered...)
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// Memo returns a memoized version of the recursive function f.
+//
+// Instead of calling itself directly, f should call recurse, which returns
+// cached results when they are available.  This allows dynamic programming
+// solutions to be written as the natural recursion:
+//
+//	fib := Memo(func(fib func(int) int, n int) int {
+//		if n < 2 {
+//			return n
+//		}
+//		return fib(n-1) + fib(n-2)
+//	})
+//
+// The cache is unbounded and is not safe for concurrent use; use NewMemoizer
+// or NewSyncMemoizer for more control.
+func Memo[K comparable, V any](f func(recurse func(K) V, k K) V) func(K) V {
+	return NewMemoizer(f).Get
+}
+
+// MemoStats are the statistics collected by a Memoizer.
+type MemoStats struct {
+	Hits      int // calls answered from the cache
+	Misses    int // calls which needed to compute their result
+	Evictions int // results removed from the cache to respect MaxSize
+}
+
+// String returns the statistics in a human-readable form.
+func (s MemoStats) String() string {
+	return fmt.Sprintf("%d hits, %d misses, %d evictions", s.Hits, s.Misses, s.Evictions)
+}
+
+// A Memoizer caches the results of a recursive function.
+type Memoizer[K comparable, V any] struct {
+	// MaxSize is the maximum number of results to keep in the cache, or 0
+	// for no limit.  When the cache is full, the least recently used result
+	// is evicted.
+	MaxSize int
+
+	f     func(recurse func(K) V, k K) V
+	mu    *sync.Mutex // nil unless safe for concurrent use
+	cache map[K]*list.Element
+	order *list.List // of *memoEntry, most recently used first
+	stats MemoStats
+}
+
+type memoEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewMemoizer returns a Memoizer for f, which should call recurse instead of
+// calling itself directly (see Memo).
+//
+// The returned Memoizer is not safe for concurrent use.
+func NewMemoizer[K comparable, V any](f func(recurse func(K) V, k K) V) *Memoizer[K, V] {
+	m := &Memoizer[K, V]{f: f}
+	m.Reset()
+	return m
+}
+
+// NewSyncMemoizer is like NewMemoizer, but the returned Memoizer is safe for
+// concurrent use.
+//
+// The lock is not held while f is running, so that it can recurse (and other
+// goroutines can make progress), which means that concurrent callers may both
+// compute the result for the same key.  The function must be deterministic.
+func NewSyncMemoizer[K comparable, V any](f func(recurse func(K) V, k K) V) *Memoizer[K, V] {
+	m := NewMemoizer(f)
+	m.mu = new(sync.Mutex)
+	return m
+}
+
+// Get returns f(k), using the cached result if there is one.
+func (m *Memoizer[K, V]) Get(k K) V {
+	if v, ok := m.lookup(k); ok {
+		return v
+	}
+	v := m.f(m.Get, k)
+	m.store(k, v)
+	return v
+}
+
+func (m *Memoizer[K, V]) lookup(k K) (v V, ok bool) {
+	m.lock()
+	defer m.unlock()
+
+	elem, ok := m.cache[k]
+	if !ok {
+		m.stats.Misses++
+		return v, false
+	}
+	m.stats.Hits++
+	m.order.MoveToFront(elem)
+	return elem.Value.(*memoEntry[K, V]).value, true
+}
+
+func (m *Memoizer[K, V]) store(k K, v V) {
+	m.lock()
+	defer m.unlock()
+
+	if elem, ok := m.cache[k]; ok {
+		// Another caller (or a deeper recursion) already computed it.
+		elem.Value.(*memoEntry[K, V]).value = v
+		m.order.MoveToFront(elem)
+		return
+	}
+	m.cache[k] = m.order.PushFront(&memoEntry[K, V]{key: k, value: v})
+	for m.MaxSize > 0 && m.order.Len() > m.MaxSize {
+		oldest := m.order.Remove(m.order.Back()).(*memoEntry[K, V])
+		delete(m.cache, oldest.key)
+		m.stats.Evictions++
+	}
+}
+
+// Len returns the number of results currently cached.
+func (m *Memoizer[K, V]) Len() int {
+	m.lock()
+	defer m.unlock()
+	return m.order.Len()
+}
+
+// Stats returns the statistics collected since the Memoizer was created or
+// last Reset.
+func (m *Memoizer[K, V]) Stats() MemoStats {
+	m.lock()
+	defer m.unlock()
+	return m.stats
+}
+
+// Reset clears the cache and the statistics.
+func (m *Memoizer[K, V]) Reset() {
+	m.lock()
+	defer m.unlock()
+	m.cache = make(map[K]*list.Element)
+	m.order = list.New()
+	m.stats = MemoStats{}
+}
+
+func (m *Memoizer[K, V]) lock() {
+	if m.mu != nil {
+		m.mu.Lock()
+	}
+}
+
+func (m *Memoizer[K, V]) unlock() {
+	if m.mu != nil {
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func fibonacci(fib func(int) int, n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func TestMemo(t *testing.T) {
+	fib := Memo(fibonacci)
+	if got, want := fib(90), 2880067194370816120; got != want {
+		t.Errorf("fib(90) = %v, want %v", got, want)
+	}
+}
+
+func TestMemoizer(t *testing.T) {
+	calls := make(map[int]int)
+	m := NewMemoizer(func(fib func(int) int, n int) int {
+		calls[n]++
+		return fibonacci(fib, n)
+	})
+
+	if got, want := m.Get(30), 832040; got != want {
+		t.Errorf("Get(30) = %v, want %v", got, want)
+	}
+	for n, count := range calls {
+		if count != 1 {
+			t.Errorf("f(%d) called %d times, want 1", n, count)
+		}
+	}
+	if got, want := m.Stats(), (MemoStats{Hits: 28, Misses: 31}); got != want {
+		t.Errorf("Stats() = %v, want %v", got, want)
+	}
+	if got, want := m.Len(), 31; got != want {
+		t.Errorf("Len() = %v, want %v", got, want)
+	}
+
+	m.Get(30)
+	if got, want := m.Stats().Hits, 29; got != want {
+		t.Errorf("Stats().Hits after repeat = %v, want %v", got, want)
+	}
+
+	m.Reset()
+	if got, want := m.Stats(), (MemoStats{}); got != want {
+		t.Errorf("Stats() after Reset = %v, want %v", got, want)
+	}
+	if got, want := m.Len(), 0; got != want {
+		t.Errorf("Len() after Reset = %v, want %v", got, want)
+	}
+}
+
+func TestMemoizerMaxSize(t *testing.T) {
+	m := NewMemoizer(fibonacci)
+	m.MaxSize = 3
+
+	// Fibonacci only ever needs the two previous results, so a small cache
+	// is still enough to keep it linear.
+	if got, want := m.Get(80), 23416728348467685; got != want {
+		t.Errorf("Get(80) = %v, want %v", got, want)
+	}
+	if got, want := m.Len(), 3; got != want {
+		t.Errorf("Len() = %v, want %v", got, want)
+	}
+	stats := m.Stats()
+	if got, want := stats.Evictions, stats.Misses-3; got != want {
+		t.Errorf("Evictions = %v, want %v (%v)", got, want, stats)
+	}
+	if stats.Misses > 100 {
+		t.Errorf("Misses = %v, want a linear number of calls", stats.Misses)
+	}
+
+	// The most recent results should still be cached.
+	before := m.Stats().Hits
+	m.Get(80)
+	if got, want := m.Stats().Hits, before+1; got != want {
+		t.Errorf("Hits after Get(80) = %v, want %v", got, want)
+	}
+}
+
+func TestSyncMemoizer(t *testing.T) {
+	m := NewSyncMemoizer(func(collatz func(int) int, n int) int {
+		switch {
+		case n == 1:
+			return 0
+		case n%2 == 0:
+			return 1 + collatz(n/2)
+		default:
+			return 1 + collatz(3*n+1)
+		}
+	})
+
+	var wg sync.WaitGroup
+	results := make([]int, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for n := 1; n < 10000; n++ {
+				results[i] += m.Get(n)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		if want := results[0]; got != want {
+			t.Errorf("goroutine %d total = %v, want %v", i, got, want)
+		}
+	}
+	if got, want := m.Get(27), 111; got != want {
+		t.Errorf("Get(27) = %v, want %v", got, want)
+	}
+}
+
+func ExampleMemo() {
+	// Count the paths from the top left to the bottom right of a grid.
+	type pos struct{ r, c int }
+	paths := Memo(func(paths func(pos) int, p pos) int {
+		if p.r == 0 || p.c == 0 {
+			return 1
+		}
+		return paths(pos{p.r - 1, p.c}) + paths(pos{p.r, p.c - 1})
+	})
+	fmt.Println(paths(pos{16, 16}))
+
+	// Output:
+	// 601080390
+}
@@ -14,47 +14,458 @@
 
 package advent
 
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// Signed is a constraint that permits any signed integer type.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is a constraint that permits any unsigned integer type.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is a constraint that permits any integer type.
+//
+// It is equivalent to golang.org/x/exp/constraints.Integer.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// Ordered is a constraint that permits any type supporting the < operator.
+//
+// It is equivalent to golang.org/x/exp/constraints.Ordered.
+type Ordered interface {
+	Integer | ~float32 | ~float64 | ~string
+}
+
+// Abs returns the absolute value of x.
+func Abs[T Signed](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Sign returns -1, 0, or 1 depending on whether x is negative, zero, or
+// positive.
+func Sign[T Signed](x T) T {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+// Min returns the smallest of the given values.
+func Min[T Integer](first T, rest ...T) T {
+	smallest := first
+	for _, v := range rest {
+		if v < smallest {
+			smallest = v
+		}
+	}
+	return smallest
+}
+
+// Max returns the largest of the given values.
+func Max[T Integer](first T, rest ...T) T {
+	largest := first
+	for _, v := range rest {
+		if v > largest {
+			largest = v
+		}
+	}
+	return largest
+}
+
+// Sum returns the sum of the given values, or 0 if there are none.
+func Sum[T Integer](values ...T) T {
+	var sum T
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// Product returns the product of the given values, or 1 if there are none.
+func Product[T Integer](values ...T) T {
+	product := T(1)
+	for _, v := range values {
+		product *= v
+	}
+	return product
+}
+
+// Clamp returns x limited to the range [lo, hi].
+func Clamp[T Integer](x, lo, hi T) T {
+	if lo > hi {
+		panic(fmt.Sprintf("Clamp: empty range [%v, %v]", lo, hi))
+	}
+	switch {
+	case x < lo:
+		return lo
+	case x > hi:
+		return hi
+	}
+	return x
+}
+
 // GCD returns the greatest common divisor of x and y.
-func GCD(x, y int) int {
+func GCD[T Integer](x, y T) T {
 	for y != 0 {
 		x, y = y, x%y
 	}
 	return x
 }
 
-// factorize returns the prime factorization of value as the keys of a map whose
-// values are the number of times the factor multiplies into n.
-func factorize(value int) map[int]int {
+// Factorize returns the prime factorization of value as the keys of a map whose
+// values are the number of times the factor multiplies into value.
+//
+// Small factors are found by trial division up to the square root of value.
+// Once the remaining cofactor is too large for that to be fast, it is split
+// using Pollard's rho algorithm.  Factorize panics if value is not positive.
+func Factorize(value int) map[int]int {
+	if value < 1 {
+		panic(fmt.Sprintf("Factorize(%d): value must be positive", value))
+	}
 	factors := make(map[int]int)
-	for divisor := 2; value > 1; divisor++ {
-		for value%divisor == 0 {
-			factors[divisor]++
-			value /= divisor
+	for _, p := range trialPrimes {
+		if p*p > value {
+			break
 		}
+		for value%p == 0 {
+			factors[p]++
+			value /= p
+		}
+	}
+	if value > 1 {
+		factorizeLarge(value, factors)
 	}
 	return factors
 }
 
+// trialDivisionLimit is the largest divisor used by trial division in
+// Factorize, after which it switches to Pollard's rho.
+const trialDivisionLimit = 1 << 12
+
+var trialPrimes = smallPrimes(trialDivisionLimit)
+
+// factorizeLarge adds the prime factors of value, which has no factors below
+// trialDivisionLimit, to factors.
+func factorizeLarge(value int, factors map[int]int) {
+	if value == 1 {
+		return
+	}
+	if IsPrime(value) {
+		factors[value]++
+		return
+	}
+	d := pollardRho(value)
+	factorizeLarge(d, factors)
+	factorizeLarge(value/d, factors)
+}
+
+// pollardRho returns a non-trivial divisor of the odd composite n.
+func pollardRho(n int) int {
+	for c := 1; ; c++ {
+		f := func(x int) int { return AddMod(MulMod(x, x, n), c, n) }
+
+		// Brent's variant of cycle detection, which batches up the
+		// differences so that GCD only needs to be computed occasionally.
+		const batch = 128
+		var (
+			x, y, ys = 2, 2, 2
+			q, d     = 1, 1
+		)
+		for r := 1; d == 1; r *= 2 {
+			x = y
+			for i := 0; i < r; i++ {
+				y = f(y)
+			}
+			for k := 0; k < r && d == 1; k += batch {
+				ys = y
+				for i := 0; i < batch && i < r-k; i++ {
+					y = f(y)
+					q = MulMod(q, Abs(x-y), n)
+				}
+				d = GCD(q, n)
+			}
+		}
+		if d == n {
+			// The batch overshot the divisor, so step through it one
+			// at a time.
+			for d = 1; d == 1; {
+				ys = f(ys)
+				d = GCD(Abs(x-ys), n)
+			}
+		}
+		if d != n {
+			return d
+		}
+		// This choice of c was unlucky, try another.
+	}
+}
+
+// millerRabinBases is a set of witnesses which make Miller-Rabin
+// deterministic for all 64-bit integers.
+var millerRabinBases = [...]int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// IsPrime returns whether n is prime using a deterministic Miller-Rabin test.
+func IsPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range millerRabinBases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	// n-1 = d * 2^s
+	d, s := n-1, 0
+	for d%2 == 0 {
+		d, s = d/2, s+1
+	}
+
+witnesses:
+	for _, a := range millerRabinBases {
+		x := PowMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		for r := 1; r < s; r++ {
+			if x = MulMod(x, x, n); x == n-1 {
+				continue witnesses
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Divisors returns all positive divisors of n in increasing order.
+func Divisors(n int) []int {
+	divisors := []int{1}
+	for p, count := range Factorize(n) {
+		existing := len(divisors)
+		pk := 1
+		for k := 0; k < count; k++ {
+			pk *= p
+			for _, d := range divisors[:existing] {
+				divisors = append(divisors, d*pk)
+			}
+		}
+	}
+	sort.Ints(divisors)
+	return divisors
+}
+
+// Totient returns Euler's totient of n, the number of integers in [1, n] which
+// are coprime to n.
+func Totient(n int) int {
+	phi := n
+	for p := range Factorize(n) {
+		phi = phi / p * (p - 1)
+	}
+	return phi
+}
+
 // LCM returns the least common multiple of the given values.
-func LCM(values ...int) int {
+func LCM[T Integer](values ...T) T {
 	if len(values) == 0 {
 		panic("LCM requires at least one value")
 	}
 
-	max := factorize(values[0]) // max[factor] = highest power
-	for _, value := range values[1:] {
-		for factor, count := range factorize(value) {
-			if current := max[factor]; count > current {
-				max[factor] = count
-			}
+	lcm := T(1)
+	for _, value := range values {
+		if value == 0 {
+			return 0
 		}
+		lcm = lcm / GCD(lcm, value) * value
+	}
+	if lcm < 0 {
+		lcm = -lcm
 	}
+	return lcm
+}
 
-	lcm := 1
-	for factor, count := range max {
-		for i := 0; i < count; i++ {
-			lcm *= factor
+// Mod returns x modulo m in the range [0, m), even for negative x.
+func Mod(x, m int) int {
+	if x %= m; x < 0 {
+		x += m
+	}
+	return x
+}
+
+// ExtendedGCD returns the greatest common divisor of a and b along with the
+// Bézout coefficients x and y such that a*x + b*y = g.
+func ExtendedGCD(a, b int) (g, x, y int) {
+	x0, x1, y0, y1 := 1, 0, 0, 1
+	for b != 0 {
+		q := a / b
+		a, b = b, a-q*b
+		x0, x1 = x1, x0-q*x1
+		y0, y1 = y1, y0-q*y1
+	}
+	if a < 0 {
+		return -a, -x0, -y0
+	}
+	return a, x0, y0
+}
+
+// ModInverse returns the multiplicative inverse of a modulo m, if one exists,
+// which is the case when a and m are coprime.
+func ModInverse(a, m int) (inv int, ok bool) {
+	g, x, _ := ExtendedGCD(Mod(a, m), m)
+	if g != 1 {
+		return 0, false
+	}
+	return Mod(x, m), true
+}
+
+// AddMod returns (a + b) mod m without overflowing for any positive m.
+func AddMod(a, b, m int) int {
+	a, b = Mod(a, m), Mod(b, m)
+	return int((uint64(a) + uint64(b)) % uint64(m))
+}
+
+// MulMod returns (a * b) mod m without overflowing for any positive m.
+func MulMod(a, b, m int) int {
+	a, b = Mod(a, m), Mod(b, m)
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	return int(bits.Rem64(hi, lo, uint64(m)))
+}
+
+// PowMod returns (base ^ exp) mod m for non-negative exp without overflowing
+// for any positive m.
+func PowMod(base, exp, m int) int {
+	if exp < 0 {
+		panic(fmt.Sprintf("PowMod: negative exponent %d", exp))
+	}
+	result := 1 % m
+	base = Mod(base, m)
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = MulMod(result, base, m)
 		}
+		base = MulMod(base, base, m)
 	}
-	return lcm
+	return result
+}
+
+// CRT solves the system of congruences x = residues[i] (mod moduli[i]) using
+// the Chinese Remainder Theorem.  The moduli do not need to be coprime.
+//
+// The solution is returned as the smallest non-negative x along with the least
+// common multiple of the moduli, since x + k*lcm is also a solution for any k.
+// If the congruences are inconsistent, ok is false.
+//
+// CRT panics if the least common multiple of the moduli overflows an int.
+func CRT(residues, moduli []int) (x, lcm int, ok bool) {
+	if len(residues) != len(moduli) {
+		panic(fmt.Sprintf("CRT: %d residues but %d moduli", len(residues), len(moduli)))
+	}
+	x, lcm = 0, 1
+	for i, m := range moduli {
+		a := Mod(residues[i], m)
+
+		// Merge x (mod lcm) with a (mod m):
+		//   x + lcm*k = a (mod m)
+		//   lcm*k = a - x (mod m)
+		g, inv, _ := ExtendedGCD(lcm, m)
+		diff := a - Mod(x, m)
+		if diff%g != 0 {
+			return 0, 0, false
+		}
+		step := m / g
+		k := MulMod(diff/g, inv, step)
+
+		hi, next := bits.Mul64(uint64(lcm), uint64(step))
+		if hi != 0 || next > uint64(1<<63-1) {
+			panic(fmt.Sprintf("CRT: lcm of moduli %v overflows int", moduli))
+		}
+		x = AddMod(x, MulMod(lcm, k, int(next)), int(next))
+		lcm = int(next)
+	}
+	return x, lcm, true
+}
+
+// An Affine represents the affine map x -> A*x + B (mod N).
+//
+// Affine maps are closed under composition, which makes them useful for
+// puzzles that apply a long sequence of linear operations many times, such
+// as shuffling cards in 2019/day22.
+type Affine struct {
+	A, B, N int
+}
+
+// NewAffine returns the affine map x -> a*x + b (mod n).
+func NewAffine(a, b, n int) Affine {
+	return Affine{A: Mod(a, n), B: Mod(b, n), N: n}
+}
+
+// IdentityAffine returns the affine map x -> x (mod n).
+func IdentityAffine(n int) Affine {
+	return Affine{A: 1 % n, B: 0, N: n}
+}
+
+// Apply returns f(x).
+func (f Affine) Apply(x int) int {
+	return AddMod(MulMod(f.A, x, f.N), f.B, f.N)
+}
+
+// Then returns the map that applies f and then g, that is x -> g(f(x)).
+func (f Affine) Then(g Affine) Affine {
+	if f.N != g.N {
+		panic(fmt.Sprintf("Affine.Then: moduli %d and %d differ", f.N, g.N))
+	}
+	return Affine{
+		A: MulMod(g.A, f.A, f.N),
+		B: AddMod(MulMod(g.A, f.B, f.N), g.B, f.N),
+		N: f.N,
+	}
+}
+
+// Pow returns the map that applies f n times.  If n is negative, the inverse
+// of f is applied -n times, which panics if f is not invertible.
+func (f Affine) Pow(n int) Affine {
+	if n < 0 {
+		inv, ok := f.Inverse()
+		if !ok {
+			panic(fmt.Sprintf("Affine.Pow: %v is not invertible", f))
+		}
+		return inv.Pow(-n)
+	}
+	result := IdentityAffine(f.N)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result = result.Then(f)
+		}
+		f = f.Then(f)
+	}
+	return result
+}
+
+// Inverse returns the map g such that g(f(x)) = x, which exists when A and N
+// are coprime.
+func (f Affine) Inverse() (g Affine, ok bool) {
+	// y = A*x + B  =>  x = A^-1*y - A^-1*B
+	inv, ok := ModInverse(f.A, f.N)
+	if !ok {
+		return Affine{}, false
+	}
+	return Affine{A: inv, B: Mod(-MulMod(inv, f.B, f.N), f.N), N: f.N}, true
+}
+
+// String returns the map in the form "x -> A*x + B (mod N)".
+func (f Affine) String() string {
+	return fmt.Sprintf("x -> %d*x + %d (mod %d)", f.A, f.B, f.N)
 }
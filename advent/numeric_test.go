@@ -44,11 +44,40 @@ func TestFactorize(t *testing.T) {
 				10891337: 1,
 			},
 		},
+		{
+			n:    1,
+			want: map[int]int{},
+		},
+		{
+			n: 998244359987710471, // 1000000007 * 998244353
+			want: map[int]int{
+				998244353:  1,
+				1000000007: 1,
+			},
+		},
+		{
+			n: 2305843009213693951, // 2^61 - 1
+			want: map[int]int{
+				2305843009213693951: 1,
+			},
+		},
+		{
+			n: 1 << 62,
+			want: map[int]int{
+				2: 62,
+			},
+		},
+		{
+			n: 4611686014132420609, // (2^31 - 1)^2
+			want: map[int]int{
+				2147483647: 2,
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(fmt.Sprint(test.n), func(t *testing.T) {
-			if diff := cmp.Diff(factorize(test.n), test.want); diff != "" {
-				t.Errorf("factorize(%d) returned incorrect factors: (-got +want)\n%s", test.n, diff)
+			if diff := cmp.Diff(Factorize(test.n), test.want); diff != "" {
+				t.Errorf("Factorize(%d) returned incorrect factors: (-got +want)\n%s", test.n, diff)
 			}
 		})
 	}
@@ -75,6 +104,10 @@ func TestLCM(t *testing.T) {
 			v:    []int{924, 2772, 2772, 924},
 			want: 2772,
 		},
+		{
+			v:    []int{186028, 231614, 108344},
+			want: 583523031727256,
+		},
 	}
 	for _, test := range tests {
 		t.Run(fmt.Sprint(test.v), func(t *testing.T) {
@@ -84,3 +117,269 @@ func TestLCM(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPrime(t *testing.T) {
+	const limit = 100000
+	primes := make(map[int]bool)
+	for _, p := range PrimesUpTo(limit) {
+		primes[p] = true
+	}
+	for n := -1; n <= limit; n++ {
+		if got, want := IsPrime(n), primes[n]; got != want {
+			t.Errorf("IsPrime(%d) = %v, want %v", n, got, want)
+		}
+	}
+
+	tests := []struct {
+		n    int
+		want bool
+	}{
+		{3215031751, false},          // strong pseudoprime to bases 2, 3, 5 and 7
+		{3825123056546413051, false}, // strong pseudoprime to bases up to 23
+		{4611686014132420609, false}, // (2^31 - 1)^2
+		{998244359987710471, false},
+		{2147483647, true},
+		{2305843009213693951, true}, // 2^61 - 1
+		{9223372036854775783, true}, // largest int64 prime
+	}
+	for _, test := range tests {
+		if got, want := IsPrime(test.n), test.want; got != want {
+			t.Errorf("IsPrime(%d) = %v, want %v", test.n, got, want)
+		}
+	}
+}
+
+func TestDivisors(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{1, []int{1}},
+		{13, []int{1, 13}},
+		{36, []int{1, 2, 3, 4, 6, 9, 12, 18, 36}},
+		{1001, []int{1, 7, 11, 13, 77, 91, 143, 1001}},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(Divisors(test.n), test.want); diff != "" {
+			t.Errorf("Divisors(%d) returned incorrect divisors: (-got +want)\n%s", test.n, diff)
+		}
+	}
+}
+
+func TestTotient(t *testing.T) {
+	for n := 1; n <= 200; n++ {
+		want := 0
+		for k := 1; k <= n; k++ {
+			if GCD(k, n) == 1 {
+				want++
+			}
+		}
+		if got := Totient(n); got != want {
+			t.Errorf("Totient(%d) = %v, want %v", n, got, want)
+		}
+	}
+	if got, want := Totient(998244359987710471), 1000000006*998244352; got != want {
+		t.Errorf("Totient(998244359987710471) = %v, want %v", got, want)
+	}
+}
+
+func TestModInverse(t *testing.T) {
+	tests := []struct {
+		a, m   int
+		want   int
+		wantOK bool
+	}{
+		{a: 3, m: 11, want: 4, wantOK: true},
+		{a: -3, m: 11, want: 7, wantOK: true},
+		{a: 10, m: 17, want: 12, wantOK: true},
+		{a: 6, m: 9, wantOK: false},
+		{a: 2, m: 119315717514047, want: 59657858757024, wantOK: true},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d mod %d", test.a, test.m), func(t *testing.T) {
+			got, ok := ModInverse(test.a, test.m)
+			if got, want := ok, test.wantOK; got != want {
+				t.Fatalf("ModInverse(%d, %d) ok = %v, want %v", test.a, test.m, got, want)
+			}
+			if got, want := got, test.want; ok && got != want {
+				t.Errorf("ModInverse(%d, %d) = %v, want %v", test.a, test.m, got, want)
+			}
+		})
+	}
+}
+
+func TestMulPowMod(t *testing.T) {
+	const big = 1<<62 + 135 // large enough that a*b overflows
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"mul small", MulMod(7, 8, 5), 1},
+		{"mul negative", MulMod(-7, 8, 5), 4},
+		{"mul overflow", MulMod(big-1, big-1, big), 1},
+		{"mul overflow negative", MulMod(-1, big-2, big), 2},
+		{"pow zero", PowMod(12345, 0, 7), 1},
+		{"pow mod one", PowMod(12345, 0, 1), 0},
+		{"pow small", PowMod(3, 13, 1000), 323},
+		{"pow fermat", PowMod(123456789, 119315717514047-1, 119315717514047), 1},
+		{"pow overflow", PowMod(big-1, 1<<40+1, big), big - 1},
+	}
+	for _, test := range tests {
+		if got, want := test.got, test.want; got != want {
+			t.Errorf("%s = %v, want %v", test.name, got, want)
+		}
+	}
+}
+
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		name     string
+		residues []int
+		moduli   []int
+		wantX    int
+		wantLCM  int
+		wantOK   bool
+	}{
+		{
+			name:     "coprime",
+			residues: []int{2, 3, 2},
+			moduli:   []int{3, 5, 7},
+			wantX:    23,
+			wantLCM:  105,
+			wantOK:   true,
+		},
+		{
+			name:     "not coprime",
+			residues: []int{3, 7},
+			moduli:   []int{6, 10},
+			wantX:    27,
+			wantLCM:  30,
+			wantOK:   true,
+		},
+		{
+			name:     "inconsistent",
+			residues: []int{1, 2},
+			moduli:   []int{4, 6},
+			wantOK:   false,
+		},
+		{
+			name:     "negative residues",
+			residues: []int{-1, -2},
+			moduli:   []int{4, 9},
+			wantX:    7,
+			wantLCM:  36,
+			wantOK:   true,
+		},
+		{
+			name:     "bus schedule",
+			residues: []int{0, -1, -4, -6, -7},
+			moduli:   []int{7, 13, 59, 31, 19},
+			wantX:    1068781,
+			wantLCM:  7 * 13 * 59 * 31 * 19,
+			wantOK:   true,
+		},
+		{
+			name:    "empty",
+			wantX:   0,
+			wantLCM: 1,
+			wantOK:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			x, lcm, ok := CRT(test.residues, test.moduli)
+			if got, want := ok, test.wantOK; got != want {
+				t.Fatalf("CRT(%v, %v) ok = %v, want %v", test.residues, test.moduli, got, want)
+			}
+			if !ok {
+				return
+			}
+			if got, want := x, test.wantX; got != want {
+				t.Errorf("CRT(%v, %v) x = %v, want %v", test.residues, test.moduli, got, want)
+			}
+			if got, want := lcm, test.wantLCM; got != want {
+				t.Errorf("CRT(%v, %v) lcm = %v, want %v", test.residues, test.moduli, got, want)
+			}
+		})
+	}
+}
+
+func TestAffine(t *testing.T) {
+	const n = 10007
+	var (
+		cut   = NewAffine(1, -3, n)  // cut 3
+		deal  = NewAffine(7, 0, n)   // deal with increment 7
+		stack = NewAffine(-1, -1, n) // deal into new stack
+	)
+	shuffle := cut.Then(deal).Then(stack)
+
+	t.Run("then", func(t *testing.T) {
+		for _, x := range []int{0, 1, 2019, n - 1} {
+			if got, want := shuffle.Apply(x), stack.Apply(deal.Apply(cut.Apply(x))); got != want {
+				t.Errorf("shuffle(%d) = %v, want %v", x, got, want)
+			}
+		}
+	})
+
+	t.Run("pow", func(t *testing.T) {
+		want := IdentityAffine(n)
+		for i := 0; i <= 100; i++ {
+			if got := shuffle.Pow(i); got != want {
+				t.Errorf("shuffle.Pow(%d) = %v, want %v", i, got, want)
+			}
+			want = want.Then(shuffle)
+		}
+	})
+
+	t.Run("inverse", func(t *testing.T) {
+		inv, ok := shuffle.Inverse()
+		if !ok {
+			t.Fatalf("%v.Inverse() is not invertible", shuffle)
+		}
+		if got, want := shuffle.Then(inv), IdentityAffine(n); got != want {
+			t.Errorf("shuffle.Then(inverse) = %v, want %v", got, want)
+		}
+		if got, want := shuffle.Pow(-5).Then(shuffle.Pow(5)), IdentityAffine(n); got != want {
+			t.Errorf("shuffle.Pow(-5).Then(shuffle.Pow(5)) = %v, want %v", got, want)
+		}
+		if _, ok := NewAffine(4, 1, 8).Inverse(); ok {
+			t.Errorf("NewAffine(4, 1, 8).Inverse() should not be invertible")
+		}
+	})
+}
+
+func TestIntegerHelpers(t *testing.T) {
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"Abs(int)", Abs(-5), 5},
+		{"Abs(int8)", Abs(int8(-128 + 1)), int8(127)},
+		{"Abs(int64)", Abs(int64(1 << 40)), int64(1 << 40)},
+		{"Sign(int)", []int{Sign(-7), Sign(0), Sign(7)}, []int{-1, 0, 1}},
+		{"Min(int)", Min(3, 1, 2), 1},
+		{"Min(uint64)", Min(uint64(1<<63), 7), uint64(7)},
+		{"Max(int8)", Max(int8(-3), -1, -2), int8(-1)},
+		{"Max(single)", Max(42), 42},
+		{"Sum(int8)", Sum[int8](1, 2, 3), int8(6)},
+		{"Sum(empty)", Sum[uint64](), uint64(0)},
+		{"Product(int64)", Product[int64](1<<20, 1<<20, 1<<20), int64(1 << 60)},
+		{"Product(empty)", Product[int](), 1},
+		{"Clamp(below)", Clamp(-3, 0, 10), 0},
+		{"Clamp(above)", Clamp(uint64(30), 0, 10), uint64(10)},
+		{"Clamp(within)", Clamp(int8(5), 0, 10), int8(5)},
+		{"GCD(int)", GCD(12, 18), 6},
+		{"GCD(uint64)", GCD(uint64(1<<63), 1<<40*3), uint64(1 << 40)},
+		{"GCD(int8)", GCD(int8(64), 48), int8(16)},
+		{"LCM(int64)", LCM[int64](4, 6, 10), int64(60)},
+		{"LCM(uint64)", LCM(uint64(1<<32), 3<<31), uint64(3 << 32)},
+		{"LCM(negative)", LCM(-4, 6), 12},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(test.got, test.want); diff != "" {
+			t.Errorf("%s returned incorrect value: (-got +want)\n%s", test.name, diff)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocr
+
+// small is the 4x6 font, used in most years (e.g. 2016/day08, 2019/day08).
+var small = map[rune]string{
+	'A': `
+.##.
+#..#
+#..#
+####
+#..#
+#..#`,
+	'B': `
+###.
+#..#
+###.
+#..#
+#..#
+###.`,
+	'C': `
+.##.
+#..#
+#...
+#...
+#..#
+.##.`,
+	'E': `
+####
+#...
+###.
+#...
+#...
+####`,
+	'F': `
+####
+#...
+###.
+#...
+#...
+#...`,
+	'G': `
+.##.
+#..#
+#...
+#.##
+#..#
+.###`,
+	'H': `
+#..#
+#..#
+####
+#..#
+#..#
+#..#`,
+	'I': `
+###
+.#.
+.#.
+.#.
+.#.
+###`,
+	'J': `
+..##
+...#
+...#
+...#
+#..#
+.##.`,
+	'K': `
+#..#
+#.#.
+##..
+#.#.
+#.#.
+#..#`,
+	'L': `
+#...
+#...
+#...
+#...
+#...
+####`,
+	'O': `
+.##.
+#..#
+#..#
+#..#
+#..#
+.##.`,
+	'P': `
+###.
+#..#
+#..#
+###.
+#...
+#...`,
+	'R': `
+###.
+#..#
+#..#
+###.
+#.#.
+#..#`,
+	'S': `
+.###
+#...
+#...
+.##.
+...#
+###.`,
+	'U': `
+#..#
+#..#
+#..#
+#..#
+#..#
+.##.`,
+	'Y': `
+#...#
+#...#
+.#.#.
+..#..
+..#..
+..#..`,
+	'Z': `
+####
+...#
+..#.
+.#..
+#...
+####`,
+}
+
+// large is the 6x10 font, used in e.g. 2018/day10.
+var large = map[rune]string{
+	'A': `
+..##..
+.#..#.
+#....#
+#....#
+#....#
+######
+#....#
+#....#
+#....#
+#....#`,
+	'B': `
+#####.
+#....#
+#....#
+#....#
+#####.
+#....#
+#....#
+#....#
+#....#
+#####.`,
+	'C': `
+.####.
+#....#
+#.....
+#.....
+#.....
+#.....
+#.....
+#.....
+#....#
+.####.`,
+	'E': `
+######
+#.....
+#.....
+#.....
+#####.
+#.....
+#.....
+#.....
+#.....
+######`,
+	'F': `
+######
+#.....
+#.....
+#.....
+#####.
+#.....
+#.....
+#.....
+#.....
+#.....`,
+	'G': `
+.####.
+#....#
+#.....
+#.....
+#.....
+#..###
+#....#
+#....#
+#...##
+.###.#`,
+	'H': `
+#....#
+#....#
+#....#
+#....#
+######
+#....#
+#....#
+#....#
+#....#
+#....#`,
+	'J': `
+...###
+....#.
+....#.
+....#.
+....#.
+....#.
+....#.
+#...#.
+#...#.
+.###..`,
+	'K': `
+#....#
+#...#.
+#..#..
+#.#...
+##....
+##....
+#.#...
+#..#..
+#...#.
+#....#`,
+	'L': `
+#.....
+#.....
+#.....
+#.....
+#.....
+#.....
+#.....
+#.....
+#.....
+######`,
+	'N': `
+#....#
+##...#
+##...#
+#.#..#
+#.#..#
+#..#.#
+#..#.#
+#...##
+#...##
+#....#`,
+	'P': `
+#####.
+#....#
+#....#
+#....#
+#####.
+#.....
+#.....
+#.....
+#.....
+#.....`,
+	'R': `
+#####.
+#....#
+#....#
+#....#
+#####.
+#..#..
+#...#.
+#...#.
+#....#
+#....#`,
+	'X': `
+#....#
+#....#
+.#..#.
+.#..#.
+..##..
+..##..
+.#..#.
+.#..#.
+#....#
+#....#`,
+	'Z': `
+######
+.....#
+.....#
+....#.
+...#..
+..#...
+.#....
+#.....
+#.....
+######`,
+}
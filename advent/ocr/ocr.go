@@ -0,0 +1,181 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ocr recognizes the block letters that some Advent of Code puzzles
+// use to render their answers.
+//
+// Both the 4x6 font (e.g. 2019/day08) and the 6x10 font (e.g. 2018/day10) are
+// supported.  Any pixel other than ' ', '.', and 0 is considered to be lit, so
+// images can be drawn with '#', 'X', etc.  Images passed as strings are read
+// one rune per pixel, so they can also be drawn with e.g. '█'.
+package ocr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+	"github.com/kylelemons/adventofcodesolutions/advent/coords"
+)
+
+// ErrUnknownGlyph is wrapped by the errors returned when a glyph can't be read.
+var ErrUnknownGlyph = errors.New("unknown glyph")
+
+// fonts maps from glyph height to a map from glyph pattern to letter.
+var fonts = map[int]map[string]rune{}
+
+func init() {
+	for _, font := range []map[rune]string{small, large} {
+		for letter, art := range font {
+			rows := strings.Split(strings.TrimPrefix(art, "\n"), "\n")
+			img := make([][]byte, len(rows))
+			for i, row := range rows {
+				img[i] = []byte(row)
+			}
+			if fonts[len(img)] == nil {
+				fonts[len(img)] = make(map[string]rune)
+			}
+			fonts[len(img)][pattern(img, 0, width(img))] = letter
+		}
+	}
+}
+
+func lit(ch byte) bool { return ch != ' ' && ch != '.' && ch != 0 }
+
+func width(img [][]byte) (w int) {
+	for _, row := range img {
+		if len(row) > w {
+			w = len(row)
+		}
+	}
+	return w
+}
+
+// litAt returns whether the pixel is lit, allowing for ragged rows.
+func litAt(img [][]byte, r, c int) bool {
+	return c < len(img[r]) && lit(img[r][c])
+}
+
+// pattern returns the canonical form of the columns [c0,c1) of the image.
+func pattern(img [][]byte, c0, c1 int) string {
+	var sb strings.Builder
+	for r := range img {
+		if r > 0 {
+			sb.WriteByte('\n')
+		}
+		for c := c0; c < c1; c++ {
+			if litAt(img, r, c) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Read returns the letters drawn in the image.
+//
+// Blank rows around the letters are ignored, and letters are separated by
+// blank columns.  If a glyph is not recognized, the returned string will have
+// a '?' in its place and the error will show the glyph.
+func Read(img [][]byte) (string, error) {
+	// Trim blank rows.
+	blankRow := func(r int) bool {
+		for c := range img[r] {
+			if lit(img[r][c]) {
+				return false
+			}
+		}
+		return true
+	}
+	for len(img) > 0 && blankRow(0) {
+		img = img[1:]
+	}
+	for len(img) > 0 && blankRow(len(img)-1) {
+		img = img[:len(img)-1]
+	}
+	if len(img) == 0 {
+		return "", nil
+	}
+
+	font, ok := fonts[len(img)]
+	if !ok {
+		return "", fmt.Errorf("letters are %d pixels tall, want 6 or 10", len(img))
+	}
+
+	blankCol := func(c int) bool {
+		for r := range img {
+			if litAt(img, r, c) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var out strings.Builder
+	var errs []string
+	for c, w := 0, width(img); c < w; {
+		if blankCol(c) {
+			c++
+			continue
+		}
+		end := c
+		for end < w && !blankCol(end) {
+			end++
+		}
+		glyph := pattern(img, c, end)
+		if letter, ok := font[glyph]; ok {
+			out.WriteRune(letter)
+		} else {
+			out.WriteByte('?')
+			errs = append(errs, fmt.Sprintf("columns %d-%d:\n%s", c, end-1, glyph))
+		}
+		c = end
+	}
+	if len(errs) > 0 {
+		return out.String(), fmt.Errorf("reading %q: %w at %s", out.String(), ErrUnknownGlyph, strings.Join(errs, "\nand at "))
+	}
+	return out.String(), nil
+}
+
+// ReadString returns the letters drawn in the newline-separated string, such as
+// one returned by advent.String2DMap.
+func ReadString(s string) (string, error) {
+	var img [][]byte
+	for _, row := range strings.Split(s, "\n") {
+		pixels := make([]byte, 0, len(row))
+		for _, r := range row {
+			if r < 0x80 && !lit(byte(r)) {
+				pixels = append(pixels, ' ')
+			} else {
+				pixels = append(pixels, '#')
+			}
+		}
+		img = append(img, pixels)
+	}
+	return Read(img)
+}
+
+// ReadLines returns the letters drawn in the image rows.
+func ReadLines(rows []string) (string, error) {
+	return ReadString(strings.Join(rows, "\n"))
+}
+
+// ReadMap returns the letters drawn in the map, where missing coordinates are
+// considered blank.
+func ReadMap(m map[coords.Coord]byte) (string, error) {
+	return ReadString(advent.String2DMap(m))
+}
@@ -0,0 +1,165 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocr
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/adventofcodesolutions/advent/coords"
+)
+
+// render draws the letters of the font side by side with gap blank columns
+// between them.
+func render(font map[rune]string, letters string, gap int) []string {
+	var out []string
+	for i, letter := range letters {
+		rows := strings.Split(strings.TrimPrefix(font[letter], "\n"), "\n")
+		if out == nil {
+			out = make([]string, len(rows))
+		}
+		for r, row := range rows {
+			if i > 0 {
+				out[r] += strings.Repeat(" ", gap)
+			}
+			out[r] += strings.NewReplacer(".", " ").Replace(row)
+		}
+	}
+	return out
+}
+
+func TestFonts(t *testing.T) {
+	for name, font := range map[string]map[rune]string{"small": small, "large": large} {
+		var letters []rune
+		for letter := range font {
+			letters = append(letters, letter)
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+		want := string(letters)
+
+		got, err := ReadLines(render(font, want, 1))
+		if err != nil {
+			t.Errorf("%s: Read(all letters): %s", name, err)
+		}
+		if got != want {
+			t.Errorf("%s: Read(all letters) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name  string
+		image []string
+		want  string
+	}{
+		{
+			name: "2019/day08",
+			image: []string{
+				"#  # ####  ##  #### #  # ",
+				"#  #    # #  #    # #  # ",
+				"####   #  #      #  #  # ",
+				"#  #  #   #     #   #  # ",
+				"#  # #    #  # #    #  # ",
+				"#  # ####  ##  ####  ##  ",
+			},
+			want: "HZCZU",
+		},
+		{
+			name: "2018/day10",
+			image: []string{
+				"",
+				"  ##       ###  ######  #    #  #    #  #    #  #    #  ######",
+				" #  #       #        #  ##   #  #    #  #    #  #   #   #     ",
+				"#    #      #        #  ##   #   #  #   #    #  #  #    #     ",
+				"#    #      #       #   # #  #   #  #   #    #  # #     #     ",
+				"#    #      #      #    # #  #    ##    ######  ##      ##### ",
+				"######      #     #     #  # #    ##    #    #  ##      #     ",
+				"#    #      #    #      #  # #   #  #   #    #  # #     #     ",
+				"#    #  #   #   #       #   ##   #  #   #    #  #  #    #     ",
+				"#    #  #   #   #       #   ##  #    #  #    #  #   #   #     ",
+				"#    #   ###    ######  #    #  #    #  #    #  #    #  ######",
+				"",
+			},
+			want: "AJZNXHKE",
+		},
+		{
+			name: "dots and blocks",
+			image: []string{
+				".██..███..",
+				"█..█.█..█.",
+				"█..█.███..",
+				"████.█..█.",
+				"█..█.█..█.",
+				"█..█.███..",
+			},
+			want: "AB",
+		},
+		{
+			name:  "empty",
+			image: []string{"", "    "},
+			want:  "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ReadLines(test.image)
+			if err != nil {
+				t.Fatalf("Read: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("Read = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReadMap(t *testing.T) {
+	m := make(map[coords.Coord]byte)
+	for r, row := range render(small, "PLU", 1) {
+		for c := range row {
+			if row[c] == '#' {
+				m[coords.RC(r+10, c-5)] = '#'
+			} else if c%2 == 0 {
+				m[coords.RC(r+10, c-5)] = '.'
+			}
+		}
+	}
+	if got, err := ReadMap(m); err != nil || got != "PLU" {
+		t.Errorf("ReadMap = %q, %v, want %q", got, err, "PLU")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	img := render(small, "AB", 1)
+	img[0] = img[0] + " #"
+	img[5] = img[5] + " #"
+	got, err := ReadLines(img)
+	if !errors.Is(err, ErrUnknownGlyph) {
+		t.Fatalf("Read error = %v, want %v", err, ErrUnknownGlyph)
+	}
+	if want := "AB?"; got != want {
+		t.Errorf("Read = %q, want %q", got, want)
+	}
+	if want := "columns 10-10:\n#\n.\n.\n.\n.\n#"; !strings.Contains(err.Error(), want) {
+		t.Errorf("Read error = %q, want it to show the glyph %q", err, want)
+	}
+
+	if _, err := ReadLines([]string{"#", "#", "#"}); err == nil {
+		t.Errorf("Read of 3-row image succeeded, want error")
+	}
+}
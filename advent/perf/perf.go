@@ -0,0 +1,165 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package perf measures the running time and allocations of solutions and
+// compares them against a baseline to find slow or regressed solutions.
+package perf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// DefaultBudget is the classic Advent of Code promise: every problem has a
+// solution that completes in at most 15 seconds on ten-year-old hardware.
+const DefaultBudget = 15 * time.Second
+
+// Duration is a time.Duration which is stored in reports as a string like
+// "1.5ms" so that they are easy to read and review.
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) { return []byte(time.Duration(d).String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	*d = Duration(v)
+	return err
+}
+
+// A Measurement is the cost of running a solution once.
+type Measurement struct {
+	Wall   Duration // elapsed wall time
+	Allocs uint64   // number of heap allocations
+	Bytes  uint64   // total bytes allocated
+}
+
+// Measure runs f and returns its cost.
+//
+// Allocations are measured for the whole process, so other goroutines should
+// be idle while f runs.
+func Measure(f func()) Measurement {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	f()
+	wall := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return Measurement{
+		Wall:   Duration(wall),
+		Allocs: after.Mallocs - before.Mallocs,
+		Bytes:  after.TotalAlloc - before.TotalAlloc,
+	}
+}
+
+// A Report holds measurements keyed by solution name (e.g. "2021/day01/part1").
+type Report map[string]Measurement
+
+// Load reads a report from a JSON file.
+func Load(path string) (Report, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing report %q: %w", path, err)
+	}
+	return r, nil
+}
+
+// Save writes the report to a JSON file.
+func (r Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Limits configure which measurements are flagged by Check.
+type Limits struct {
+	// Budget is the maximum wall time for any solution (0 disables).
+	Budget time.Duration
+
+	// Regression is the fraction (e.g. 0.25 for 25%) by which wall time or
+	// allocations may grow relative to the baseline before being flagged
+	// (0 disables).
+	Regression float64
+
+	// Noise is the minimum change in wall time that is considered a
+	// regression, so that very fast solutions aren't flagged due to jitter.
+	Noise time.Duration
+}
+
+// A Finding describes a solution which exceeded a limit.
+type Finding struct {
+	Name    string
+	Problem string
+}
+
+// String returns the finding in human-readable form.
+func (f Finding) String() string { return f.Name + ": " + f.Problem }
+
+// Check compares the current measurements against the limits and the
+// baseline, which may be nil.  Solutions missing from the baseline are only
+// checked against the budget.  Findings are sorted by name.
+func Check(current, baseline Report, limits Limits) []Finding {
+	var findings []Finding
+	flag := func(name, format string, args ...interface{}) {
+		findings = append(findings, Finding{name, fmt.Sprintf(format, args...)})
+	}
+
+	for name, cur := range current {
+		wall := time.Duration(cur.Wall)
+		if limits.Budget > 0 && wall > limits.Budget {
+			flag(name, "took %v, over budget of %v", wall, limits.Budget)
+		}
+
+		base, ok := baseline[name]
+		if !ok || limits.Regression <= 0 {
+			continue
+		}
+		baseWall := time.Duration(base.Wall)
+		if growth(float64(baseWall), float64(wall)) > limits.Regression && wall-baseWall > limits.Noise {
+			flag(name, "took %v, %s slower than baseline %v", wall, percent(float64(baseWall), float64(wall)), baseWall)
+		}
+		if growth(float64(base.Allocs), float64(cur.Allocs)) > limits.Regression {
+			flag(name, "made %d allocations, %s more than baseline %d", cur.Allocs, percent(float64(base.Allocs), float64(cur.Allocs)), base.Allocs)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Name < findings[j].Name })
+	return findings
+}
+
+// growth returns the fractional growth from base to cur.
+func growth(base, cur float64) float64 {
+	if base <= 0 {
+		if cur > 0 {
+			return cur
+		}
+		return 0
+	}
+	return (cur - base) / base
+}
+
+func percent(base, cur float64) string {
+	return fmt.Sprintf("%.0f%%", 100*growth(base, cur))
+}
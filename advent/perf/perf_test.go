@@ -0,0 +1,94 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perf
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var sink []byte
+
+func TestMeasure(t *testing.T) {
+	m := Measure(func() {
+		for i := 0; i < 10; i++ {
+			sink = make([]byte, 1024)
+		}
+		time.Sleep(time.Millisecond)
+	})
+	if m.Allocs < 10 || m.Bytes < 10*1024 {
+		t.Errorf("Measure = %+v, want at least 10 allocations of 10KiB", m)
+	}
+	if time.Duration(m.Wall) < time.Millisecond {
+		t.Errorf("Measure.Wall = %v, want at least 1ms", time.Duration(m.Wall))
+	}
+}
+
+func ms(n float64) Duration { return Duration(n * float64(time.Millisecond)) }
+
+func TestCheck(t *testing.T) {
+	baseline := Report{
+		"2018/day09/part2": {Wall: ms(900), Allocs: 1000},
+		"2019/day16/part2": {Wall: ms(2000), Allocs: 10},
+		"2021/day01/part1": {Wall: ms(0.1), Allocs: 5},
+		"2021/day01/part2": {Wall: ms(3), Allocs: 5},
+	}
+	current := Report{
+		"2018/day09/part2": {Wall: ms(20000), Allocs: 1000}, // over budget and slower
+		"2019/day16/part2": {Wall: ms(2100), Allocs: 10},    // within 20%
+		"2021/day01/part1": {Wall: ms(0.5), Allocs: 5},      // 400% slower, but within noise
+		"2021/day01/part2": {Wall: ms(3), Allocs: 50},       // more allocations
+		"2021/day02/part1": {Wall: ms(16000)},               // new, over budget
+	}
+	limits := Limits{
+		Budget:     DefaultBudget,
+		Regression: 0.2,
+		Noise:      10 * time.Millisecond,
+	}
+
+	var got []string
+	for _, f := range Check(current, baseline, limits) {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"2018/day09/part2: took 20s, over budget of 15s",
+		"2018/day09/part2: took 20s, 2122% slower than baseline 900ms",
+		"2021/day01/part2: made 50 allocations, 900% more than baseline 5",
+		"2021/day02/part1: took 16s, over budget of 15s",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Check findings differ: (-got +want)\n%s", diff)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perf.json")
+	want := Report{
+		"2021/day01/part1": {Wall: ms(1.5), Allocs: 3, Bytes: 4096},
+	}
+	if err := want.Save(path); err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Load(Save(r)) differs: (-got +want)\n%s", diff)
+	}
+}
@@ -14,16 +14,19 @@
 
 package advent
 
-// RangeTracker is a helper for tracking the min/max of integers.
+// RangeTracker is a helper for tracking the min/max of ints.
 //
 // Its zero value is usable, but Min/Max should not be considered if !Valid.
-type RangeTracker struct {
+type RangeTracker = RangeTrackerOf[int]
+
+// RangeTrackerOf is a RangeTracker for any integer type.
+type RangeTrackerOf[T Integer] struct {
 	Valid    bool // true if Track has been called
-	Min, Max int
+	Min, Max T
 }
 
-// Track updates the tracker based on the int, and returns it for easy chaining.
-func (rt *RangeTracker) Track(n int) int {
+// Track updates the tracker based on the value, and returns it for easy chaining.
+func (rt *RangeTrackerOf[T]) Track(n T) T {
 	if !rt.Valid {
 		rt.Min = n
 		rt.Max = n
@@ -40,14 +43,14 @@ func (rt *RangeTracker) Track(n int) int {
 }
 
 // TrackAll is like Track but tracks all of the given numbers, not returning anything.
-func (rt *RangeTracker) TrackAll(n ...int) {
+func (rt *RangeTrackerOf[T]) TrackAll(n ...T) {
 	for _, n := range n {
 		rt.Track(n)
 	}
 }
 
 // Delta returns the size of the range.
-func (rt *RangeTracker) Delta() int {
+func (rt *RangeTrackerOf[T]) Delta() T {
 	if !rt.Valid {
 		panic("Delta called on invalid RangeTracker")
 	}
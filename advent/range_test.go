@@ -0,0 +1,45 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"testing"
+)
+
+func TestRangeTracker(t *testing.T) {
+	var rt RangeTracker
+	if got, want := rt.Track(5), 5; got != want {
+		t.Errorf("Track(5) = %v, want %v", got, want)
+	}
+	rt.TrackAll(3, 9, 4)
+	if got, want := rt, (RangeTracker{Valid: true, Min: 3, Max: 9}); got != want {
+		t.Errorf("after tracking, range = %+v, want %+v", got, want)
+	}
+	if got, want := rt.Delta(), 6; got != want {
+		t.Errorf("Delta() = %v, want %v", got, want)
+	}
+
+	var small RangeTrackerOf[int8]
+	small.TrackAll(-100, 20, -3)
+	if got, want := small, (RangeTrackerOf[int8]{Valid: true, Min: -100, Max: 20}); got != want {
+		t.Errorf("after tracking int8s, range = %+v, want %+v", got, want)
+	}
+
+	var large RangeTrackerOf[uint64]
+	large.TrackAll(1<<63, 1<<62)
+	if got, want := large.Delta(), uint64(1<<62); got != want {
+		t.Errorf("Delta() = %v, want %v", got, want)
+	}
+}
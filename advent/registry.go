@@ -0,0 +1,88 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// A Solution is a registered solution to one part of a puzzle.
+type Solution struct {
+	Year, Day, Part int
+
+	// Func computes the answer from the puzzle input.
+	Func func(t OptionalT, input string) any
+}
+
+// String returns the solution's name, e.g. "2021/day01/part1".
+func (s Solution) String() string {
+	return fmt.Sprintf("%d/day%02d/part%d", s.Year, s.Day, s.Part)
+}
+
+var registry struct {
+	sync.Mutex
+	solutions map[[3]int]Solution // keyed by {year, day, part}
+}
+
+// Register registers the solution for one part of a puzzle so that it can be
+// run outside of a test, typically from an init function:
+//
+//	func init() {
+//		advent.Register(2021, 1, 1, func(t advent.OptionalT, in string) any { return part1(t, in) })
+//	}
+//
+// Register panics if a solution has already been registered for the part.
+func Register(year, day, part int, f func(t OptionalT, input string) any) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	sol := Solution{Year: year, Day: day, Part: part, Func: f}
+	key := [3]int{year, day, part}
+	if _, ok := registry.solutions[key]; ok {
+		panic(fmt.Sprintf("advent.Register: %v registered twice", sol))
+	}
+	if registry.solutions == nil {
+		registry.solutions = make(map[[3]int]Solution)
+	}
+	registry.solutions[key] = sol
+}
+
+// Solutions returns the registered solutions which match the given filter,
+// sorted by year, day, and part.  Zero values in the filter match anything,
+// so Solutions(0, 0, 0) returns every solution.
+func Solutions(year, day, part int) []Solution {
+	registry.Lock()
+	defer registry.Unlock()
+
+	var sols []Solution
+	for _, sol := range registry.solutions {
+		if (year == 0 || sol.Year == year) && (day == 0 || sol.Day == day) && (part == 0 || sol.Part == part) {
+			sols = append(sols, sol)
+		}
+	}
+	sort.Slice(sols, func(i, j int) bool {
+		a, b := sols[i], sols[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		return a.Part < b.Part
+	})
+	return sols
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package advent
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegister(t *testing.T) {
+	solve := func(t OptionalT, in string) any { return len(in) }
+	Register(1901, 2, 1, solve)
+	Register(1901, 1, 2, solve)
+	Register(1901, 1, 1, solve)
+	Register(1900, 25, 2, solve)
+
+	tests := []struct {
+		year, day, part int
+		want            []string
+	}{
+		{1901, 0, 0, []string{"1901/day01/part1", "1901/day01/part2", "1901/day02/part1"}},
+		{1901, 1, 0, []string{"1901/day01/part1", "1901/day01/part2"}},
+		{1900, 0, 2, []string{"1900/day25/part2"}},
+		{1900, 1, 0, nil},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, sol := range Solutions(test.year, test.day, test.part) {
+			got = append(got, sol.String())
+			if ans := sol.Func(t, "abc"); ans != 3 {
+				t.Errorf("%v returned %v, want 3", sol, ans)
+			}
+		}
+		if diff := cmp.Diff(got, test.want); diff != "" {
+			t.Errorf("Solutions(%d, %d, %d) differ: (-got +want)\n%s", test.year, test.day, test.part, diff)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("duplicate Register did not panic")
+		} else if got, want := fmt.Sprint(r), "advent.Register: 1901/day01/part1 registered twice"; got != want {
+			t.Errorf("duplicate Register panicked with %q, want %q", got, want)
+		}
+	}()
+	Register(1901, 1, 1, solve)
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package submit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// An Attempt is a submitted answer and its verdict.
+type Attempt struct {
+	Answer  string
+	Verdict Verdict
+	Time    time.Time
+}
+
+// A Ledger is a file-backed record of the answers that have been submitted.
+//
+// All methods on Ledger are safe to call concurrently.
+type Ledger struct {
+	path string
+
+	mu       sync.Mutex
+	attempts map[string][]Attempt // key is from ledgerKey
+}
+
+func ledgerKey(year, day, part int) string {
+	return fmt.Sprintf("%d/%02d/%d", year, day, part)
+}
+
+// OpenLedger loads the ledger stored at path.  If the file does not exist,
+// an empty ledger is returned, and the file will be created when the first
+// attempt is recorded.
+func OpenLedger(path string) (*Ledger, error) {
+	l := &Ledger{
+		path:     path,
+		attempts: make(map[string][]Attempt),
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &l.attempts); err != nil {
+		return nil, fmt.Errorf("parsing ledger %q: %w", path, err)
+	}
+	return l, nil
+}
+
+// Attempts returns the answers that have been tried for the given puzzle part.
+func (l *Ledger) Attempts(year, day, part int) []Attempt {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Attempt(nil), l.attempts[ledgerKey(year, day, part)]...)
+}
+
+// Check returns the result of submitting the answer if it can be determined
+// from previous attempts.  In addition to exact matches, an answer is known to
+// be wrong if a different answer was correct, or if it is numerically beyond
+// an answer which was too high or too low.
+func (l *Ledger) Check(year, day, part int, answer string) (*Result, bool) {
+	attempts := l.Attempts(year, day, part)
+
+	for _, a := range attempts {
+		if a.Answer == answer && a.Verdict.Final() {
+			return &Result{
+				Verdict: a.Verdict,
+				Message: fmt.Sprintf("%q was already submitted at %s", answer, a.Time.Format(time.Stamp)),
+				Cached:  true,
+			}, true
+		}
+	}
+	for _, a := range attempts {
+		if a.Verdict == Correct {
+			return &Result{
+				Verdict: Incorrect,
+				Message: fmt.Sprintf("already solved; the correct answer was %q", a.Answer),
+				Cached:  true,
+			}, true
+		}
+	}
+
+	n, err := strconv.ParseInt(answer, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	for _, a := range attempts {
+		prev, err := strconv.ParseInt(a.Answer, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case a.Verdict == TooHigh && n >= prev:
+			return &Result{
+				Verdict: TooHigh,
+				Message: fmt.Sprintf("%d is not less than %d, which was too high", n, prev),
+				Cached:  true,
+			}, true
+		case a.Verdict == TooLow && n <= prev:
+			return &Result{
+				Verdict: TooLow,
+				Message: fmt.Sprintf("%d is not greater than %d, which was too low", n, prev),
+				Cached:  true,
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// Record records an attempt and saves the ledger.
+func (l *Ledger) Record(year, day, part int, answer string, verdict Verdict) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := ledgerKey(year, day, part)
+	l.attempts[key] = append(l.attempts[key], Attempt{
+		Answer:  answer,
+		Verdict: verdict,
+		Time:    time.Now(),
+	})
+
+	data, err := json.MarshalIndent(l.attempts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a crash can't corrupt the ledger.
+	tmp := l.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
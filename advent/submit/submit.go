@@ -0,0 +1,228 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package submit posts Advent of Code answers and keeps a ledger of the
+// answers that have been tried, so that a known-wrong answer is never
+// submitted twice.
+package submit
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kylelemons/adventofcodesolutions/advent/input"
+)
+
+// A Verdict is the website's response to an answer.
+type Verdict int
+
+// Verdicts.
+const (
+	Unknown     Verdict = iota // the response could not be understood
+	Correct                    // the answer is correct
+	Incorrect                  // the answer is wrong (with no further hint)
+	TooHigh                    // the answer is wrong and too high
+	TooLow                     // the answer is wrong and too low
+	RateLimited                // an answer was submitted too recently
+	WrongLevel                 // the part is locked or already solved
+)
+
+var verdictNames = [...]string{
+	Unknown:     "unknown",
+	Correct:     "correct",
+	Incorrect:   "incorrect",
+	TooHigh:     "too high",
+	TooLow:      "too low",
+	RateLimited: "rate limited",
+	WrongLevel:  "wrong level",
+}
+
+// String returns the human-readable name of the verdict.
+func (v Verdict) String() string {
+	if v < 0 || int(v) >= len(verdictNames) {
+		return fmt.Sprintf("Verdict(%d)", int(v))
+	}
+	return verdictNames[v]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v Verdict) MarshalText() ([]byte, error) { return []byte(v.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *Verdict) UnmarshalText(text []byte) error {
+	for i, name := range verdictNames {
+		if name == string(text) {
+			*v = Verdict(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown verdict %q", text)
+}
+
+// Final returns true if the verdict will not change if the same answer is
+// submitted again.
+func (v Verdict) Final() bool {
+	switch v {
+	case Correct, Incorrect, TooHigh, TooLow:
+		return true
+	}
+	return false
+}
+
+// A Result is the outcome of submitting an answer.
+type Result struct {
+	Verdict Verdict
+
+	// Wait is how long the website asked us to wait before submitting again.
+	Wait time.Duration
+
+	// Message is the text of the website's response, or an explanation of why
+	// the answer was not submitted.
+	Message string
+
+	// Cached is true if the result came from the ledger instead of the website.
+	Cached bool
+}
+
+// A Client submits answers.
+type Client struct {
+	*input.Client
+
+	// Ledger records submitted answers.  If nil, answers are not recorded.
+	Ledger *Ledger
+}
+
+// FromEnv returns a Client configured from the environment (see input.FromEnv)
+// which records answers in the ledger in the input cache directory.
+func FromEnv() (*Client, error) {
+	c := &Client{Client: input.FromEnv()}
+	if c.CacheDir != "" {
+		ledger, err := OpenLedger(filepath.Join(c.CacheDir, "ledger.json"))
+		if err != nil {
+			return nil, err
+		}
+		c.Ledger = ledger
+	}
+	return c, nil
+}
+
+// Submit submits the answer for the given part of the puzzle.
+//
+// If the ledger already knows the result of submitting this answer, it is
+// returned without contacting the website.  Otherwise, the answer is posted
+// and the result is recorded in the ledger.
+func (c *Client) Submit(year, day, part int, answer string) (*Result, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil, fmt.Errorf("empty answer")
+	}
+	if part != 1 && part != 2 {
+		return nil, fmt.Errorf("invalid part %d: must be 1 or 2", part)
+	}
+	if c.Ledger != nil {
+		if res, ok := c.Ledger.Check(year, day, part, answer); ok {
+			return res, nil
+		}
+	}
+	if c.Session == "" {
+		return nil, input.ErrNoSession
+	}
+
+	form := url.Values{
+		"level":  {strconv.Itoa(part)},
+		"answer": {answer},
+	}
+	req, err := c.NewRequest("POST", c.URL("/%d/day/%d/answer", year, day), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("submitting %d day %d part %d: %w", year, day, part, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("submitting %d day %d part %d: %s", year, day, part, resp.Status)
+	}
+
+	res := ParseResponse(string(body))
+	if c.Ledger != nil && res.Verdict.Final() {
+		if err := c.Ledger.Record(year, day, part, answer, res.Verdict); err != nil {
+			return res, fmt.Errorf("recording answer: %w", err)
+		}
+	}
+	return res, nil
+}
+
+var (
+	articleRE = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRE     = regexp.MustCompile(`<[^>]*>`)
+	spaceRE   = regexp.MustCompile(`\s+`)
+	leftRE    = regexp.MustCompile(`You have ((?:\d+m )?\d+s) left to wait`)
+	minutesRE = regexp.MustCompile(`wait (one|\d+) minutes?`)
+)
+
+// ParseResponse interprets the HTML returned after submitting an answer.
+func ParseResponse(body string) *Result {
+	msg := body
+	if m := articleRE.FindStringSubmatch(body); m != nil {
+		msg = m[1]
+	}
+	msg = tagRE.ReplaceAllString(msg, "")
+	msg = html.UnescapeString(msg)
+	msg = strings.TrimSpace(spaceRE.ReplaceAllString(msg, " "))
+
+	res := &Result{Message: msg}
+	switch {
+	case strings.Contains(msg, "That's the right answer"):
+		res.Verdict = Correct
+	case strings.Contains(msg, "That's not the right answer"):
+		res.Verdict = Incorrect
+		switch {
+		case strings.Contains(msg, "too high"):
+			res.Verdict = TooHigh
+		case strings.Contains(msg, "too low"):
+			res.Verdict = TooLow
+		}
+	case strings.Contains(msg, "You gave an answer too recently"):
+		res.Verdict = RateLimited
+	case strings.Contains(msg, "You don't seem to be solving the right level"):
+		res.Verdict = WrongLevel
+	}
+
+	if m := leftRE.FindStringSubmatch(msg); m != nil {
+		res.Wait, _ = time.ParseDuration(strings.Replace(m[1], " ", "", -1))
+	} else if m := minutesRE.FindStringSubmatch(msg); m != nil {
+		minutes := 1
+		if m[1] != "one" {
+			minutes, _ = strconv.Atoi(m[1])
+		}
+		res.Wait = time.Duration(minutes) * time.Minute
+	}
+	return res
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package submit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/adventofcodesolutions/advent/input"
+)
+
+func page(article string) string {
+	return `<!DOCTYPE html><html><body><main>
+<article><p>` + article + `</p></article>
+</main></body></html>`
+}
+
+const (
+	rightAnswer = `That's the right answer!  You are <span class="day-success">one gold star</span> closer to saving Christmas. <a href="/2019/day/18#part2">[Continue to Part Two]</a>`
+	tooHigh     = `That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data; there are also some general tips on the <a href="/2019/about">about page</a>, or you can ask for hints on the <a href="https://www.reddit.com/r/adventofcode/" target="_blank">subreddit</a>.  Please wait one minute before trying again. (You guessed <span style="white-space:nowrap;"><code>5000</code>.)</span> <a href="/2019/day/18">[Return to Day 18]</a>`
+	tooLow      = `That's not the right answer; your answer is too low.  Please wait 5 minutes before trying again. <a href="/2019/day/18">[Return to Day 18]</a>`
+	wrong       = `That&apos;s not the right answer.  If you&apos;re stuck, make sure you&apos;re using the full input data.  Please wait one minute before trying again.`
+	tooRecent   = `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 4m 12s left to wait. <a href="/2019/day/18">[Return to Day 18]</a>`
+	wrongLevel  = `You don't seem to be solving the right level.  Did you already complete it? <a href="/2019/day/18">[Return to Day 18]</a>`
+)
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		article string
+		verdict Verdict
+		wait    time.Duration
+	}{
+		{"correct", rightAnswer, Correct, 0},
+		{"too high", tooHigh, TooHigh, time.Minute},
+		{"too low", tooLow, TooLow, 5 * time.Minute},
+		{"wrong", wrong, Incorrect, time.Minute},
+		{"too recent", tooRecent, RateLimited, 4*time.Minute + 12*time.Second},
+		{"seconds only", "You gave an answer too recently. You have 37s left to wait.", RateLimited, 37 * time.Second},
+		{"wrong level", wrongLevel, WrongLevel, 0},
+		{"gibberish", "Server is on fire", Unknown, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := ParseResponse(page(test.article))
+			if got, want := res.Verdict, test.verdict; got != want {
+				t.Errorf("Verdict = %v, want %v (message %q)", got, want, res.Message)
+			}
+			if got, want := res.Wait, test.wait; got != want {
+				t.Errorf("Wait = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// fakeSite serves answers for 2019 day 18, for which the correct answers are
+// 4250 for part 1 and 1640 for part 2.
+func fakeSite(t *testing.T) (*httptest.Server, *int) {
+	var posts int
+	answers := map[string]int{"1": 4250, "2": 1640}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/2019/day/18/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		posts++
+		if c, err := r.Cookie("session"); err != nil || c.Value != "secret" {
+			http.Error(w, "not logged in", http.StatusBadRequest)
+			return
+		}
+		want, ok := answers[r.FormValue("level")]
+		if !ok {
+			fmt.Fprint(w, page(wrongLevel))
+			return
+		}
+		got, err := strconv.Atoi(r.FormValue("answer"))
+		switch {
+		case err != nil:
+			fmt.Fprint(w, page(wrong))
+		case got > want:
+			fmt.Fprint(w, page(tooHigh))
+		case got < want:
+			fmt.Fprint(w, page(tooLow))
+		default:
+			fmt.Fprint(w, page(rightAnswer))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &posts
+}
+
+func TestSubmit(t *testing.T) {
+	srv, posts := fakeSite(t)
+	ledgerPath := filepath.Join(t.TempDir(), "ledger.json")
+	ledger, err := OpenLedger(ledgerPath)
+	if err != nil {
+		t.Fatalf("OpenLedger: %s", err)
+	}
+	c := &Client{
+		Client: &input.Client{BaseURL: srv.URL, Session: "secret"},
+		Ledger: ledger,
+	}
+
+	steps := []struct {
+		part    int
+		answer  string
+		verdict Verdict
+		cached  bool
+	}{
+		{1, "5000", TooHigh, false},
+		{1, "5000", TooHigh, true},
+		{1, "6000", TooHigh, true},
+		{1, "100", TooLow, false},
+		{1, "99", TooLow, true},
+		{1, "abc", Incorrect, false},
+		{1, "abc", Incorrect, true},
+		{1, "4250", Correct, false},
+		{1, "4250", Correct, true},
+		{1, "4251", Incorrect, true},
+		{2, "4250", TooHigh, false},
+		{3, "1", 0, false},
+	}
+	wantPosts := 0
+	for _, step := range steps {
+		res, err := c.Submit(2019, 18, step.part, step.answer)
+		if step.part == 3 {
+			if err == nil {
+				t.Errorf("Submit(part %d) succeeded, want error", step.part)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Submit(part %d, %q): %s", step.part, step.answer, err)
+		}
+		if !step.cached {
+			wantPosts++
+		}
+		if res.Verdict != step.verdict || res.Cached != step.cached {
+			t.Errorf("Submit(part %d, %q) = %v (cached=%v), want %v (cached=%v): %s",
+				step.part, step.answer, res.Verdict, res.Cached, step.verdict, step.cached, res.Message)
+		}
+	}
+	if got, want := *posts, wantPosts; got != want {
+		t.Errorf("server saw %d submissions, want %d", got, want)
+	}
+
+	// The ledger should persist across restarts.
+	reopened, err := OpenLedger(ledgerPath)
+	if err != nil {
+		t.Fatalf("OpenLedger (reopen): %s", err)
+	}
+	if got, want := len(reopened.Attempts(2019, 18, 1)), 4; got != want {
+		t.Errorf("reopened ledger has %d part 1 attempts, want %d", got, want)
+	}
+	if res, ok := reopened.Check(2019, 18, 1, "5000"); !ok || res.Verdict != TooHigh {
+		t.Errorf("reopened ledger Check(5000) = %+v, %v, want %v", res, ok, TooHigh)
+	}
+}
+
+func TestSubmitRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, page(tooRecent))
+	}))
+	defer srv.Close()
+
+	ledger, err := OpenLedger(filepath.Join(t.TempDir(), "ledger.json"))
+	if err != nil {
+		t.Fatalf("OpenLedger: %s", err)
+	}
+	c := &Client{
+		Client: &input.Client{BaseURL: srv.URL, Session: "secret"},
+		Ledger: ledger,
+	}
+	res, err := c.Submit(2019, 18, 1, "42")
+	if err != nil {
+		t.Fatalf("Submit: %s", err)
+	}
+	if res.Verdict != RateLimited || res.Wait != 4*time.Minute+12*time.Second {
+		t.Errorf("Submit = %v (wait %v), want %v (wait 4m12s)", res.Verdict, res.Wait, RateLimited)
+	}
+	if got := ledger.Attempts(2019, 18, 1); len(got) != 0 {
+		t.Errorf("rate limited attempt was recorded: %+v", got)
+	}
+}
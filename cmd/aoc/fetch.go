@@ -0,0 +1,80 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/kylelemons/adventofcodesolutions/advent/input"
+)
+
+var fetchFlags struct {
+	dayFlags
+	Output string
+	Force  bool
+}
+
+var fetchCmd = &command{
+	Name:  "fetch",
+	Short: "Download a puzzle input into the local cache",
+	Setup: func(fs *flag.FlagSet) {
+		fetchFlags.Register(fs)
+		fs.StringVar(&fetchFlags.Output, "o", "", "Also write the input to this file (- for stdout)")
+		fs.BoolVar(&fetchFlags.Force, "force", false, "Download the input even if it has been cached")
+	},
+	Run: runFetch,
+}
+
+func runFetch(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments: %q", args)
+	}
+	if err := fetchFlags.Check(); err != nil {
+		return err
+	}
+	year, day := fetchFlags.Year, fetchFlags.Day
+
+	client := input.FromEnv()
+	var in string
+	var err error
+	if fetchFlags.Force {
+		// Only replace the cached input once the download has succeeded.
+		if in, err = client.Fetch(year, day); err == nil {
+			err = client.Store(year, day, in)
+		}
+	} else {
+		in, err = client.Get(year, day)
+	}
+	if err != nil {
+		return err
+	}
+	if path := client.CachePath(year, day); path != "" {
+		log.Printf("%d day %d cached in %s", year, day, path)
+	}
+
+	switch fetchFlags.Output {
+	case "":
+	case "-":
+		fmt.Print(in)
+	default:
+		if err := ioutil.WriteFile(fetchFlags.Output, []byte(in), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command aoc is a helper for working on Advent of Code solutions.
+//
+// Usage:
+//
+//	aoc <command> [flags] [args]
+//
+// Run "aoc help" for the list of commands.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kylelemons/adventofcodesolutions/advent/input"
+)
+
+// A command is a subcommand of aoc.
+type command struct {
+	Name  string
+	Usage string // arguments, after the flags
+	Short string // one-line description
+
+	// Flags is populated by Setup and parsed before Run is called.
+	Flags *flag.FlagSet
+	Setup func(fs *flag.FlagSet)
+	Run   func(args []string) error
+}
+
+// commands is the list of subcommands, in the order they are listed by help.
+var commands = []*command{
+	newCmd,
+	fetchCmd,
+	submitCmd,
+	runCmd,
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: aoc <command> [flags] [args]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.Name, cmd.Short)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun \"aoc help <command>\" for the flags of a command.\n")
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("aoc: ")
+
+	for _, cmd := range commands {
+		cmd := cmd
+		cmd.Flags = flag.NewFlagSet(cmd.Name, flag.ExitOnError)
+		cmd.Flags.Usage = func() {
+			fmt.Fprintf(os.Stderr, "Usage: %s\n\n%s.\n\nFlags:\n", strings.TrimSpace("aoc "+cmd.Name+" [flags] "+cmd.Usage), cmd.Short)
+			cmd.Flags.PrintDefaults()
+		}
+		if cmd.Setup != nil {
+			cmd.Setup(cmd.Flags)
+		}
+	}
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name, args := os.Args[1], os.Args[2:]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		if len(args) > 0 {
+			if cmd := lookup(args[0]); cmd != nil {
+				cmd.Flags.Usage()
+				return
+			}
+		}
+		usage()
+		return
+	}
+
+	cmd := lookup(name)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "aoc: unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+	cmd.Flags.Parse(args)
+	if err := cmd.Run(cmd.Flags.Args()); err != nil {
+		log.Fatalf("%s: %s", cmd.Name, err)
+	}
+}
+
+func lookup(name string) *command {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// dayFlags are the flags used to select a puzzle.
+type dayFlags struct {
+	Year, Day int
+}
+
+// Register adds -year and -day flags which default to the puzzle whose
+// directory is the current directory, if any.  If the current directory is not
+// a solution directory, year defaults to the most recent event and day must be
+// provided explicitly.
+func (d *dayFlags) Register(fs *flag.FlagSet) {
+	year, day := latestYear(), 0
+	if wd, err := os.Getwd(); err == nil {
+		if y, d, ok := input.DayFromPath(wd); ok {
+			year, day = y, d
+		}
+	}
+	fs.IntVar(&d.Year, "year", year, "Puzzle year")
+	fs.IntVar(&d.Day, "day", day, "Puzzle day (1-25)")
+}
+
+// Check returns an error if the day is not set.
+func (d *dayFlags) Check() error {
+	if d.Day == 0 {
+		return fmt.Errorf("-day is required outside of a solution directory")
+	}
+	return nil
+}
+
+// latestYear returns the year of the most recent event that has started.
+func latestYear() int {
+	now := time.Now().UTC()
+	if now.Month() < time.December {
+		return now.Year() - 1
+	}
+	return now.Year()
+}
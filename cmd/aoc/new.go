@@ -0,0 +1,191 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kylelemons/adventofcodesolutions/advent/input"
+)
+
+const modulePath = "github.com/kylelemons/adventofcodesolutions"
+
+var newFlags struct {
+	dayFlags
+	Root    string
+	Example int
+	Input   bool
+}
+
+var newCmd = &command{
+	Name:  "new",
+	Short: "Create a solution directory for a day from the skeleton",
+	Setup: func(fs *flag.FlagSet) {
+		newFlags.Register(fs)
+		fs.StringVar(&newFlags.Root, "root", "", "Repository root (default: found from the current directory)")
+		fs.IntVar(&newFlags.Example, "example", -1, "Pre-fill the example from this <pre><code> block of the puzzle (0 for the first)")
+		fs.BoolVar(&newFlags.Input, "input", true, "Populate input.txt if the input is cached or can be downloaded")
+	},
+	Run: runNew,
+}
+
+func runNew(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments: %q", args)
+	}
+	if err := newFlags.Check(); err != nil {
+		return err
+	}
+	year, day := newFlags.Year, newFlags.Day
+
+	root := newFlags.Root
+	if root == "" {
+		var err error
+		if root, err = findRoot(); err != nil {
+			return err
+		}
+	}
+
+	client := input.FromEnv()
+	var example string
+	if n := newFlags.Example; n >= 0 {
+		puzzle, err := client.Puzzle(year, day)
+		if err != nil {
+			return err
+		}
+		examples := input.Examples(puzzle)
+		if n >= len(examples) {
+			return fmt.Errorf("puzzle has %d code blocks, -example=%d is out of range", len(examples), n)
+		}
+		example = examples[n]
+	}
+
+	var in []byte
+	if newFlags.Input {
+		if s, err := client.Get(year, day); err != nil {
+			log.Printf("Not populating input.txt: %s", err)
+		} else {
+			in = []byte(s)
+		}
+	}
+
+	dir, err := scaffold(root, year, day, example, in)
+	if err != nil {
+		return err
+	}
+	fmt.Println(dir)
+	return nil
+}
+
+// findRoot returns the root of the repository containing the working directory.
+func findRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		mod, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil && bytes.HasPrefix(mod, []byte("module "+modulePath+"\n")) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%s not found in a parent of the current directory (use -root)", modulePath)
+		}
+		dir = parent
+	}
+}
+
+var (
+	copyrightRE   = regexp.MustCompile(`Copyright \d{4}`)
+	skelExampleRE = regexp.MustCompile(`\{"part1 example 0", "\.\.\.", 0\}`)
+)
+
+// scaffold creates the solution directory for the given day from the skeleton
+// in root/skel and returns its path.
+//
+// If example is non-empty, it replaces the placeholder example in the test
+// table.  If in is nil, the placeholder input from the skeleton is used.
+//
+// Existing files are never overwritten.
+func scaffold(root string, year, day int, example string, in []byte) (string, error) {
+	skel := filepath.Join(root, "skel")
+	src, err := ioutil.ReadFile(filepath.Join(skel, "dayNN_test.go"))
+	if err != nil {
+		return "", fmt.Errorf("reading skeleton: %w", err)
+	}
+	if in == nil {
+		if in, err = ioutil.ReadFile(filepath.Join(skel, "input.txt")); err != nil {
+			return "", fmt.Errorf("reading skeleton: %w", err)
+		}
+	}
+
+	src = copyrightRE.ReplaceAll(src, []byte("Copyright "+strconv.Itoa(year)))
+	if example != "" {
+		if !skelExampleRE.Match(src) {
+			return "", fmt.Errorf("skeleton has no placeholder example to replace")
+		}
+		lit := strconv.Quote(example)
+		if !strings.Contains(example, "`") {
+			lit = "`" + example + "`"
+		}
+		src = skelExampleRE.ReplaceAllLiteral(src, []byte(`{"part1 example 0", `+lit+`, 0}`))
+	}
+
+	dir := filepath.Join(root, strconv.Itoa(year), fmt.Sprintf("day%02d", day))
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{fmt.Sprintf("day%02d_test.go", day), src},
+		{"input.txt", in},
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(dir, f.name)); err == nil {
+			return "", fmt.Errorf("refusing to overwrite %s", filepath.Join(dir, f.name))
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		if err := writeNew(filepath.Join(dir, f.name), f.data); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// writeNew writes data to a file which must not already exist.
+func writeNew(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScaffold(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "skel"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"dayNN_test.go", "input.txt"} {
+		data, err := ioutil.ReadFile(filepath.Join("..", "..", "skel", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(root, "skel", name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir, err := scaffold(root, 2022, 9, "R 4\nU `4`", []byte("R 1\n"))
+	if err != nil {
+		t.Fatalf("scaffold: %s", err)
+	}
+	if got, want := dir, filepath.Join(root, "2022", "day09"); got != want {
+		t.Errorf("scaffold returned %q, want %q", got, want)
+	}
+
+	src, err := ioutil.ReadFile(filepath.Join(dir, "day09_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Copyright 2022 Kyle Lemons\n",
+		"// Package aocday is the entrypoint for this AoC solution.\n",
+		`{"part1 example 0", "R 4\nU ` + "`4`" + `", 0},`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, src)
+		}
+	}
+	if in, err := ioutil.ReadFile(filepath.Join(dir, "input.txt")); err != nil || string(in) != "R 1\n" {
+		t.Errorf("input.txt = %q, %v, want %q", in, err, "R 1\n")
+	}
+
+	if _, err := scaffold(root, 2022, 9, "", nil); err == nil || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Errorf("second scaffold returned %v, want overwrite error", err)
+	}
+}
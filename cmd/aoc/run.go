@@ -0,0 +1,194 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/kylelemons/adventofcodesolutions/advent"
+	"github.com/kylelemons/adventofcodesolutions/advent/perf"
+)
+
+var runFlags struct {
+	Root    string
+	Part    int
+	Verbose bool
+
+	// Performance tracking
+	Report     string
+	Baseline   string
+	Budget     time.Duration
+	Regression float64
+	Noise      time.Duration
+}
+
+var runCmd = &command{
+	Name:  "run",
+	Usage: "<year> [day]",
+	Short: "Run registered solutions on their inputs",
+	Setup: func(fs *flag.FlagSet) {
+		fs.StringVar(&runFlags.Root, "root", "", "Repository root (default: found from the current directory)")
+		fs.IntVar(&runFlags.Part, "part", 0, "Only run this part (default: both)")
+		fs.BoolVar(&runFlags.Verbose, "v", false, "Show output printed by the solutions")
+		fs.StringVar(&runFlags.Report, "report", "", "Write timing and allocations to this JSON file")
+		fs.StringVar(&runFlags.Baseline, "baseline", "", "Compare against the report in this JSON file, written by -report on this machine")
+		fs.DurationVar(&runFlags.Budget, "budget", perf.DefaultBudget, "Flag solutions which take longer than this (0 to disable)")
+		fs.Float64Var(&runFlags.Regression, "regress", 50, "Flag solutions which are this many percent slower than, or allocate more than, the baseline (0 to disable)")
+		fs.DurationVar(&runFlags.Noise, "noise", 10*time.Millisecond, "Ignore slowdowns smaller than this")
+	},
+	Run: runRun,
+}
+
+func runRun(args []string) error {
+	var year, day int
+	var err error
+	switch len(args) {
+	case 2:
+		if day, err = strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("invalid day %q", args[1])
+		}
+		fallthrough
+	case 1:
+		if year, err = strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("invalid year %q", args[0])
+		}
+	default:
+		return fmt.Errorf("want <year> [day], got %q", args)
+	}
+
+	root := runFlags.Root
+	if root == "" {
+		// Without a repository, inputs can still come from the cache.
+		root, _ = findRoot()
+	}
+
+	sols := advent.Solutions(year, day, runFlags.Part)
+	if len(sols) == 0 {
+		return fmt.Errorf("no solutions registered for year=%d day=%d part=%d", year, day, runFlags.Part)
+	}
+
+	baseline, err := loadBaseline()
+	if err != nil {
+		return err
+	}
+
+	// Results are printed as they complete, so the columns are fixed-width.
+	var failed int
+	report := make(perf.Report)
+	for _, sol := range sols {
+		res := run(sol, filepath.Join(root, strconv.Itoa(sol.Year), fmt.Sprintf("day%02d", sol.Day), "input.txt"))
+		answer := fmt.Sprint(res.Answer)
+		if res.Err != nil {
+			failed++
+			answer = "FAILED: " + res.Err.Error()
+		} else {
+			report[sol.String()] = res.Cost
+		}
+		wall := time.Duration(res.Cost.Wall).Round(time.Microsecond)
+		fmt.Printf("%d day%02d part%d  %10v  %10d allocs  %s\n", sol.Year, sol.Day, sol.Part, wall, res.Cost.Allocs, answer)
+	}
+
+	if runFlags.Report != "" {
+		if err := report.Save(runFlags.Report); err != nil {
+			return err
+		}
+	}
+
+	findings := perf.Check(report, baseline, perf.Limits{
+		Budget:     runFlags.Budget,
+		Regression: runFlags.Regression / 100,
+		Noise:      runFlags.Noise,
+	})
+	for _, f := range findings {
+		fmt.Printf("SLOW: %s\n", f)
+	}
+
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d of %d solutions failed", failed, len(sols))
+	case len(findings) > 0:
+		return fmt.Errorf("%d performance problems found", len(findings))
+	}
+	return nil
+}
+
+// loadBaseline loads the baseline report, if one was requested.
+//
+// Wall times are only comparable on the same machine, so there is no default
+// baseline; record one with -report and pass it back in with -baseline.
+func loadBaseline() (perf.Report, error) {
+	if runFlags.Baseline == "" {
+		return nil, nil
+	}
+	return perf.Load(runFlags.Baseline)
+}
+
+// A runResult is the outcome of running one solution.
+type runResult struct {
+	Answer any
+	Err    error
+	Cost   perf.Measurement // cost of the solution, excluding reading input
+}
+
+// run runs the solution on the input in the named file.
+func run(sol advent.Solution, inputFile string) (res runResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			if f, ok := r.(runFailure); ok {
+				res.Err = f
+				return
+			}
+			res.Err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	in := advent.ReadFile(runT{}, inputFile)
+
+	// Many solutions print debugging information, which would drown out the
+	// answers, so it is discarded unless requested.
+	if !runFlags.Verbose {
+		if devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+			stdout := os.Stdout
+			os.Stdout = devnull
+			defer func() {
+				os.Stdout = stdout
+				devnull.Close()
+			}()
+		}
+	}
+
+	res.Cost = perf.Measure(func() {
+		res.Answer = sol.Func(runT{}, in)
+	})
+	return res
+}
+
+// runT is an advent.OptionalT which aborts only the current solution.
+type runT struct{}
+
+type runFailure string
+
+func (f runFailure) Error() string { return string(f) }
+
+func (runT) Helper() {}
+
+func (runT) Fatalf(format string, args ...interface{}) {
+	panic(runFailure(fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,28 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// Solutions register themselves with advent.Register when they are linked in.
+import (
+	_ "github.com/kylelemons/adventofcodesolutions/2019/day18"
+	_ "github.com/kylelemons/adventofcodesolutions/2021/day01"
+	_ "github.com/kylelemons/adventofcodesolutions/2021/day02"
+	_ "github.com/kylelemons/adventofcodesolutions/2021/day03"
+	_ "github.com/kylelemons/adventofcodesolutions/2021/day04"
+	_ "github.com/kylelemons/adventofcodesolutions/2021/day05"
+	_ "github.com/kylelemons/adventofcodesolutions/2021/day06"
+	_ "github.com/kylelemons/adventofcodesolutions/2021/day07"
+	_ "github.com/kylelemons/adventofcodesolutions/2021/day08"
+)
@@ -0,0 +1,76 @@
+// Copyright 2021 Kyle Lemons
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/kylelemons/adventofcodesolutions/advent/submit"
+)
+
+var submitFlags struct {
+	dayFlags
+}
+
+var submitCmd = &command{
+	Name:  "submit",
+	Usage: "<part> <answer>",
+	Short: "Submit an answer, unless the ledger knows it is wrong",
+	Setup: func(fs *flag.FlagSet) {
+		submitFlags.Register(fs)
+	},
+	Run: runSubmit,
+}
+
+func runSubmit(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("want <part> <answer>, got %q", args)
+	}
+	if err := submitFlags.Check(); err != nil {
+		return err
+	}
+	part, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid part %q", args[0])
+	}
+	answer := args[1]
+
+	client, err := submit.FromEnv()
+	if err != nil {
+		return err
+	}
+	res, err := client.Submit(submitFlags.Year, submitFlags.Day, part, answer)
+	if err != nil {
+		return err
+	}
+
+	source := "website"
+	if res.Cached {
+		source = "ledger"
+	}
+	fmt.Printf("%d day %d part %d: %q is %s (from %s)\n", submitFlags.Year, submitFlags.Day, part, answer, res.Verdict, source)
+	if res.Message != "" {
+		fmt.Printf("  %s\n", res.Message)
+	}
+	if res.Wait > 0 {
+		fmt.Printf("  Wait %v before submitting again.\n", res.Wait)
+	}
+	if res.Verdict != submit.Correct {
+		return fmt.Errorf("answer not accepted (%s)", res.Verdict)
+	}
+	return nil
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package acoday is the entrypoint for this AoC solution.
+// Package aocday is the entrypoint for this AoC solution.
 package aocday
 
 import (